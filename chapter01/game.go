@@ -1,35 +1,77 @@
 package chapter01
 
 import (
+	"time"
+
 	"github.com/veandco/go-sdl2/sdl"
 )
 
 const thickness = 15
 const paddleHeight = 100.0
 
+// DefaultTickRate is the number of simulation steps per second.
+const DefaultTickRate = 60
+
+// maxFrameTime is the most time simulated in a single frame (in seconds).
+const maxFrameTime = 0.25
+
 type Game struct {
-	window     *sdl.Window
-	renderer   *sdl.Renderer
-	paddlePos  Vector2
-	paddleDir  int
-	ballPos    Vector2
-	ballVel    Vector2
-	ticksCount uint64
-	isRunning  bool
+	window    *sdl.Window
+	renderer  *sdl.Renderer
+	paddlePos Vector2
+	paddleDir int
+	ballPos   Vector2
+	ballVel   Vector2
+	// Positions before the last simulation step, for interpolation
+	prevPaddlePos Vector2
+	prevBallPos   Vector2
+	clock         Clock
+	lastTime      time.Duration
+	isRunning     bool
+	// Run the simulation without a window or renderer
+	headless bool
+
+	// Time between simulation steps (in seconds)
+	fixedDeltaTime float32
+	// Time not yet simulated
+	accumulator float32
+	// Fraction of a step to interpolate drawing by
+	alpha float32
 }
 
 type Vector2 struct {
 	X, Y float32
 }
 
-func NewGame() *Game {
+// Lerp returns linear interpolation from a to b by f
+func (a Vector2) Lerp(b Vector2, f float32) Vector2 {
+	return Vector2{a.X + f*(b.X-a.X), a.Y + f*(b.Y-a.Y)}
+}
+
+func NewGame(clock Clock) *Game {
 	return &Game{
-		ticksCount: 0,
-		isRunning:  true,
+		clock:          clock,
+		fixedDeltaTime: 1.0 / DefaultTickRate,
+		isRunning:      true,
 	}
 }
 
+// NewHeadlessGame creates a game that simulates without a window or renderer.
+// Use Step to advance the simulation.
+func NewHeadlessGame(clock Clock) *Game {
+	g := NewGame(clock)
+	g.headless = true
+
+	return g
+}
+
 func (g *Game) Initialize() error {
+	if g.headless {
+		g.resetPositions()
+		g.lastTime = g.clock.Now()
+		return nil
+	}
+
 	if err := sdl.Init(sdl.INIT_EVERYTHING); err != nil {
 		sdl.Log("unable to initialize SDL: %s\n", err)
 		return err
@@ -49,6 +91,14 @@ func (g *Game) Initialize() error {
 		return err
 	}
 
+	g.resetPositions()
+
+	g.lastTime = g.clock.Now()
+
+	return nil
+}
+
+func (g *Game) resetPositions() {
 	g.paddlePos.X = 10.0
 	g.paddlePos.Y = 768.0 / 2.0
 
@@ -57,17 +107,44 @@ func (g *Game) Initialize() error {
 	g.ballVel.X = -200.0
 	g.ballVel.Y = 235.0
 
-	return nil
+	g.prevPaddlePos = g.paddlePos
+	g.prevBallPos = g.ballPos
 }
 
 func (g *Game) RunLoop() {
 	for g.isRunning {
+		g.RunFrame()
+	}
+}
+
+// RunFrame runs a single iteration of the game loop, simulating
+// the time passed on the clock since the previous frame.
+func (g *Game) RunFrame() {
+	if !g.headless {
 		g.processInput()
-		g.update()
+	}
+	g.update()
+	if !g.headless {
 		g.generateOutput()
 	}
 }
 
+// Step advances the simulation by deltaTime seconds,
+// without reading input or drawing.
+func (g *Game) Step(deltaTime float32) {
+	g.updateGame(deltaTime)
+}
+
+// SetTickRate sets the number of simulation steps per second.
+func (g *Game) SetTickRate(ticksPerSecond float32) {
+	g.fixedDeltaTime = 1.0 / ticksPerSecond
+}
+
+// GetTickRate returns the number of simulation steps per second.
+func (g *Game) GetTickRate() float32 {
+	return 1.0 / g.fixedDeltaTime
+}
+
 func (g *Game) Shutdown() (err error) {
 	defer sdl.Quit()
 	defer func() {
@@ -91,37 +168,49 @@ func (g *Game) processInput() {
 			g.isRunning = false
 			return
 		}
+	}
 
-		state := sdl.GetKeyboardState()
-		if state[sdl.SCANCODE_ESCAPE] != 0 {
-			g.isRunning = false
-			return
-		}
+	state := sdl.GetKeyboardState()
+	if state[sdl.SCANCODE_ESCAPE] != 0 {
+		g.isRunning = false
+		return
+	}
 
-		g.paddleDir = 0
-		if state[sdl.SCANCODE_W] != 0 {
-			g.paddleDir -= 1
-		}
-		if state[sdl.SCANCODE_S] != 0 {
-			g.paddleDir += 1
-		}
+	g.paddleDir = 0
+	if state[sdl.SCANCODE_W] != 0 {
+		g.paddleDir -= 1
+	}
+	if state[sdl.SCANCODE_S] != 0 {
+		g.paddleDir += 1
 	}
 }
 
 func (g *Game) update() {
-	// SDL_TICKS_PASSED is not available in go-sdl2
-	for {
-		if sdl.GetTicks64() > g.ticksCount+16 {
-			break
-		}
+	// Time elapsed since last frame (converted to seconds)
+	now := g.clock.Now()
+	frameTime := float32((now - g.lastTime).Seconds())
+	g.lastTime = now
+
+	// Clamp the frame time, so a slow frame can't demand more and more
+	// steps to catch up (spiral of death)
+	if frameTime > maxFrameTime {
+		frameTime = maxFrameTime
 	}
 
-	deltaTime := float32(sdl.GetTicks64()-g.ticksCount) / 1000.0
-	if deltaTime > 0.05 {
-		deltaTime = 0.05
+	// Simulate in fixed steps until the accumulated time is used up
+	g.accumulator += frameTime
+	for g.accumulator >= g.fixedDeltaTime {
+		g.updateGame(g.fixedDeltaTime)
+		g.accumulator -= g.fixedDeltaTime
 	}
 
-	g.ticksCount = sdl.GetTicks64()
+	// How far we are between the last step and the next one
+	g.alpha = g.accumulator / g.fixedDeltaTime
+}
+
+func (g *Game) updateGame(deltaTime float32) {
+	g.prevPaddlePos = g.paddlePos
+	g.prevBallPos = g.ballPos
 
 	// update paddle position
 	if g.paddleDir != 0 {
@@ -185,10 +274,14 @@ func (g *Game) generateOutput() {
 	wall.H = 1024
 	_ = g.renderer.FillRect(&wall)
 
+	// Draw paddle and ball between their last two simulated positions
+	paddlePos := g.prevPaddlePos.Lerp(g.paddlePos, g.alpha)
+	ballPos := g.prevBallPos.Lerp(g.ballPos, g.alpha)
+
 	// Draw paddle
 	paddle := sdl.Rect{
-		X: int32(g.paddlePos.X),
-		Y: int32(g.paddlePos.Y - paddleHeight/2),
+		X: int32(paddlePos.X),
+		Y: int32(paddlePos.Y - paddleHeight/2),
 		W: thickness,
 		H: paddleHeight,
 	}
@@ -196,8 +289,8 @@ func (g *Game) generateOutput() {
 
 	// Draw ball
 	ball := sdl.Rect{
-		X: int32(g.ballPos.X - thickness/2),
-		Y: int32(g.ballPos.Y - thickness/2),
+		X: int32(ballPos.X - thickness/2),
+		Y: int32(ballPos.Y - thickness/2),
 		W: thickness,
 		H: thickness,
 	}
@@ -205,3 +298,19 @@ func (g *Game) generateOutput() {
 
 	g.renderer.Present()
 }
+
+func (g *Game) GetClock() Clock {
+	return g.clock
+}
+
+func (g *Game) IsRunning() bool {
+	return g.isRunning
+}
+
+func (g *Game) GetPaddlePosition() Vector2 {
+	return g.paddlePos
+}
+
+func (g *Game) GetBallPosition() Vector2 {
+	return g.ballPos
+}
@@ -30,13 +30,18 @@ type Actor interface {
 	SetScale(s float32)
 	GetRotation() Angle
 	SetRotation(r float32)
+	SavePreviousTransform()
+	GetInterpolatedPosition(alpha float32) Vector2
+	GetInterpolatedRotation(alpha float32) Angle
 	GetState() State
 	SetState(s State)
 	GetGame() *Game
 	AddComponent(c Component)
 	RemoveComponent(c Component)
-	Destroy()
-	DestroyComponents()
+	GetComponents() []Component
+	OnAdded()
+	OnRemoved()
+	OnDestroy()
 }
 
 type actor struct {
@@ -46,6 +51,9 @@ type actor struct {
 	rotation   Angle
 	components []Component
 	game       *Game
+	// Transform before the last update, for interpolation
+	prevPosition Vector2
+	prevRotation Angle
 }
 
 func NewActor(game *Game) Actor {
@@ -102,12 +110,37 @@ func (a *actor) SetRotation(r float32) {
 	a.rotation = Angle(r)
 }
 
+func (a *actor) SavePreviousTransform() {
+	a.prevPosition = a.position
+	a.prevRotation = a.rotation
+}
+
+func (a *actor) GetInterpolatedPosition(alpha float32) Vector2 {
+	return a.prevPosition.Lerp(a.position, alpha)
+}
+
+func (a *actor) GetInterpolatedRotation(alpha float32) Angle {
+	return a.prevRotation.Lerp(a.rotation, alpha)
+}
+
 func (a *actor) GetState() State {
 	return a.state
 }
 
 func (a *actor) SetState(s State) {
+	wasActive := a.state == Active
 	a.state = s
+
+	// Let components know when the actor starts or stops being active
+	if isActive := s == Active; isActive != wasActive {
+		for _, c := range a.components {
+			if isActive {
+				c.OnEnable()
+			} else {
+				c.OnDisable()
+			}
+		}
+	}
 }
 
 func (a *actor) GetGame() *Game {
@@ -126,21 +159,30 @@ func (a *actor) AddComponent(c Component) {
 
 	// Insert at position
 	a.components = slices.Insert(a.components, insertIndex, c)
+
+	a.game.addComponent(c)
+	c.OnAdded()
 }
 
 func (a *actor) RemoveComponent(c Component) {
+	if !slices.Contains(a.components, c) {
+		return
+	}
+
 	a.components = slices.DeleteFunc(a.components, func(c2 Component) bool {
 		return c == c2
 	})
-}
 
-func (a *actor) Destroy() {
-	a.game.RemoveActor(a)
-	a.DestroyComponents()
+	a.game.removeComponent(c)
+	c.OnRemoved()
 }
 
-func (a *actor) DestroyComponents() {
-	for len(a.components) > 0 {
-		a.components[0].Destroy()
-	}
+func (a *actor) GetComponents() []Component {
+	return a.components
 }
+
+func (a *actor) OnAdded() {}
+
+func (a *actor) OnRemoved() {}
+
+func (a *actor) OnDestroy() {}
@@ -18,3 +18,15 @@ func (a Angle) Radians() float64 {
 func (a Angle) Degrees() float64 {
 	return float64(a / Degree)
 }
+
+// Lerp returns angle interpolated from a to b by f, along the shortest arc
+func (a Angle) Lerp(b Angle, f float32) Angle {
+	diff := math.Mod(float64(b-a), 2*math.Pi)
+	if diff > math.Pi {
+		diff -= 2 * math.Pi
+	} else if diff < -math.Pi {
+		diff += 2 * math.Pi
+	}
+
+	return a + Angle(diff*float64(f))
+}
@@ -52,9 +52,3 @@ func (a *AnimSpriteComponent) GetAnimFPS() float32 {
 func (a *AnimSpriteComponent) SetAnimFPS(fps float32) {
 	a.animFPS = fps
 }
-
-func (a *AnimSpriteComponent) Destroy() {
-	owner := a.GetOwner()
-	owner.RemoveComponent(a)
-	owner.GetGame().RemoveSprite(a)
-}
@@ -5,6 +5,8 @@ import "github.com/veandco/go-sdl2/sdl"
 type BGTexture struct {
 	tex    *sdl.Texture
 	offset Vector2
+	// Offset before the last update, for interpolation
+	prevOffset Vector2
 }
 
 type BgSpriteComponent struct {
@@ -30,16 +32,19 @@ func (b *BgSpriteComponent) Update(deltaTime float32) {
 
 	for _, bg := range b.textures {
 		// Update the x offset
+		bg.prevOffset = bg.offset
 		bg.offset.X += b.scrollSpeed * deltaTime
 		// If this is completely off the screen, reset offset to
 		// the right of the last bg texture
 		if bg.offset.X < -b.screenSize.X {
 			bg.offset.X = float32((len(b.textures))-1)*b.screenSize.X - 1
+			// Don't interpolate across the screen
+			bg.prevOffset = bg.offset
 		}
 	}
 }
 
-func (b *BgSpriteComponent) Draw(renderer *sdl.Renderer) {
+func (b *BgSpriteComponent) Draw(renderer *sdl.Renderer, alpha float32) {
 	// Draw each background texture
 	for _, bg := range b.textures {
 		r := sdl.Rect{}
@@ -47,9 +52,11 @@ func (b *BgSpriteComponent) Draw(renderer *sdl.Renderer) {
 		r.W = int32(b.screenSize.X)
 		r.H = int32(b.screenSize.Y)
 		// Center the rectangle around the position of the owner
-		pos := b.GetOwner().GetPosition()
-		r.X = int32(pos.X - float32(r.W)/2 + bg.offset.X)
-		r.Y = int32(pos.Y - float32(r.H)/2 + bg.offset.Y)
+		// (interpolated between the last two updates)
+		pos := b.GetOwner().GetInterpolatedPosition(alpha)
+		offset := bg.prevOffset.Lerp(bg.offset, alpha)
+		r.X = int32(pos.X - float32(r.W)/2 + offset.X)
+		r.Y = int32(pos.Y - float32(r.H)/2 + offset.Y)
 
 		_ = renderer.Copy(bg.tex, nil, &r)
 	}
@@ -66,6 +73,7 @@ func (b *BgSpriteComponent) SetBGTextures(textures []*sdl.Texture) {
 				Y: 0,
 			},
 		}
+		bg.prevOffset = bg.offset
 		b.textures[i] = bg
 		count++
 	}
@@ -82,9 +90,3 @@ func (b *BgSpriteComponent) SetScrollSpeed(speed float32) {
 func (b *BgSpriteComponent) GetScrollSpeed() float32 {
 	return b.scrollSpeed
 }
-
-func (b *BgSpriteComponent) Destroy() {
-	owner := b.GetOwner()
-	owner.RemoveComponent(b)
-	owner.GetGame().RemoveSprite(b)
-}
@@ -6,7 +6,10 @@ type Component interface {
 	Update(deltaTime float32)
 	GetOwner() Actor
 	GetUpdateOrder() int
-	Destroy()
+	OnAdded()
+	OnRemoved()
+	OnEnable()
+	OnDisable()
 }
 
 type component struct {
@@ -34,6 +37,10 @@ func (c *component) GetUpdateOrder() int {
 	return c.updateOrder
 }
 
-func (c *component) Destroy() {
-	c.owner.RemoveComponent(c)
-}
+func (c *component) OnAdded() {}
+
+func (c *component) OnRemoved() {}
+
+func (c *component) OnEnable() {}
+
+func (c *component) OnDisable() {}
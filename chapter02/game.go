@@ -2,16 +2,33 @@ package chapter02
 
 import (
 	"slices"
+	"time"
 
 	"github.com/veandco/go-sdl2/img"
 	"github.com/veandco/go-sdl2/sdl"
 )
 
+// DefaultTickRate is the number of simulation steps per second.
+const DefaultTickRate = 60
+
+// maxFrameTime is the most time simulated in a single frame (in seconds).
+const maxFrameTime = 0.25
+
 type Game struct {
-	window     *sdl.Window
-	renderer   *sdl.Renderer
-	ticksCount uint64
-	isRunning  bool
+	window    *sdl.Window
+	renderer  *sdl.Renderer
+	clock     Clock
+	lastTime  time.Duration
+	isRunning bool
+	// Run the simulation without a window or renderer
+	headless bool
+
+	// Time between simulation steps (in seconds)
+	fixedDeltaTime float32
+	// Time not yet simulated
+	accumulator float32
+	// Fraction of a step to interpolate drawing by
+	alpha float32
 
 	textures map[string]*sdl.Texture
 	sprites  []Sprite
@@ -28,15 +45,37 @@ type Vector2 struct {
 	X, Y float32
 }
 
-func NewGame() *Game {
+// Lerp returns linear interpolation from a to b by f
+func (a Vector2) Lerp(b Vector2, f float32) Vector2 {
+	return Vector2{a.X + f*(b.X-a.X), a.Y + f*(b.Y-a.Y)}
+}
+
+func NewGame(clock Clock) *Game {
 	return &Game{
-		ticksCount: 0,
-		textures:   make(map[string]*sdl.Texture),
-		isRunning:  true,
+		clock:          clock,
+		fixedDeltaTime: 1.0 / DefaultTickRate,
+		textures:       make(map[string]*sdl.Texture),
+		isRunning:      true,
 	}
 }
 
+// NewHeadlessGame creates a game that simulates without a window or renderer.
+// Use Step to advance the simulation.
+func NewHeadlessGame(clock Clock) *Game {
+	g := NewGame(clock)
+	g.headless = true
+
+	return g
+}
+
 func (g *Game) Initialize() error {
+	if g.headless {
+		g.loadData()
+		g.savePreviousTransforms()
+		g.lastTime = g.clock.Now()
+		return nil
+	}
+
 	if err := sdl.Init(sdl.INIT_EVERYTHING); err != nil {
 		sdl.Log("unable to initialize SDL: %s\n", err)
 		return err
@@ -63,20 +102,47 @@ func (g *Game) Initialize() error {
 	}
 
 	g.loadData()
+	g.savePreviousTransforms()
 
-	g.ticksCount = sdl.GetTicks64()
+	g.lastTime = g.clock.Now()
 
 	return nil
 }
 
 func (g *Game) RunLoop() {
 	for g.isRunning {
+		g.RunFrame()
+	}
+}
+
+// RunFrame runs a single iteration of the game loop, simulating
+// the time passed on the clock since the previous frame.
+func (g *Game) RunFrame() {
+	if !g.headless {
 		g.processInput()
-		g.update()
+	}
+	g.update()
+	if !g.headless {
 		g.generateOutput()
 	}
 }
 
+// Step advances the simulation by deltaTime seconds,
+// without reading input or drawing.
+func (g *Game) Step(deltaTime float32) {
+	g.updateGame(deltaTime)
+}
+
+// SetTickRate sets the number of simulation steps per second.
+func (g *Game) SetTickRate(ticksPerSecond float32) {
+	g.fixedDeltaTime = 1.0 / ticksPerSecond
+}
+
+// GetTickRate returns the number of simulation steps per second.
+func (g *Game) GetTickRate() float32 {
+	return 1.0 / g.fixedDeltaTime
+}
+
 func (g *Game) processInput() {
 	for event := sdl.PollEvent(); event != nil; event = sdl.PollEvent() {
 		switch event.(type) {
@@ -84,32 +150,44 @@ func (g *Game) processInput() {
 			g.isRunning = false
 			return
 		}
+	}
 
-		state := sdl.GetKeyboardState()
-		if state[sdl.SCANCODE_ESCAPE] != 0 {
-			g.isRunning = false
-			return
-		}
-
-		// Process ship input
-		g.ship.ProcessKeyboard(state)
+	state := sdl.GetKeyboardState()
+	if state[sdl.SCANCODE_ESCAPE] != 0 {
+		g.isRunning = false
+		return
 	}
+
+	// Process ship input
+	g.ship.ProcessKeyboard(state)
 }
 
 func (g *Game) update() {
-	// SDL_TICKS_PASSED is not available in go-sdl2
-	for {
-		if sdl.GetTicks64() > g.ticksCount+16 {
-			break
-		}
+	// Time elapsed since last frame (converted to seconds)
+	now := g.clock.Now()
+	frameTime := float32((now - g.lastTime).Seconds())
+	g.lastTime = now
+
+	// Clamp the frame time, so a slow frame can't demand more and more
+	// steps to catch up (spiral of death)
+	if frameTime > maxFrameTime {
+		frameTime = maxFrameTime
 	}
 
-	deltaTime := float32(sdl.GetTicks64()-g.ticksCount) / 1000.0
-	if deltaTime > 0.05 {
-		deltaTime = 0.05
+	// Simulate in fixed steps until the accumulated time is used up
+	g.accumulator += frameTime
+	for g.accumulator >= g.fixedDeltaTime {
+		g.updateGame(g.fixedDeltaTime)
+		g.accumulator -= g.fixedDeltaTime
 	}
 
-	g.ticksCount = sdl.GetTicks64()
+	// How far we are between the last step and the next one
+	g.alpha = g.accumulator / g.fixedDeltaTime
+}
+
+func (g *Game) updateGame(deltaTime float32) {
+	// Keep transforms from before this update to interpolate drawing from
+	g.savePreviousTransforms()
 
 	// Update all actors
 	g.updatingActors = true
@@ -120,6 +198,7 @@ func (g *Game) update() {
 
 	// Move any pending actors to actors
 	for _, pending := range g.pendingActors {
+		pending.SavePreviousTransform()
 		g.actors = append(g.actors, pending)
 	}
 	g.pendingActors = nil
@@ -130,6 +209,12 @@ func (g *Game) update() {
 	})
 }
 
+func (g *Game) savePreviousTransforms() {
+	for _, a := range g.actors {
+		a.SavePreviousTransform()
+	}
+}
+
 func (g *Game) generateOutput() {
 	_ = g.renderer.SetDrawColor(0, 0, 0, 255)
 
@@ -138,7 +223,7 @@ func (g *Game) generateOutput() {
 
 	// Draw all sprite components
 	for _, sprite := range g.sprites {
-		sprite.Draw(g.renderer)
+		sprite.Draw(g.renderer, g.alpha)
 	}
 
 	g.renderer.Present()
@@ -165,7 +250,6 @@ func (g *Game) loadData() {
 	}
 	bg.SetBGTextures(bgTexs)
 	bg.SetScrollSpeed(-100)
-	g.AddSprite(bg)
 	tmp.AddComponent(bg)
 
 	// Create the "closer" background
@@ -177,14 +261,13 @@ func (g *Game) loadData() {
 	}
 	bg.SetBGTextures(bgTexs)
 	bg.SetScrollSpeed(-200)
-	g.AddSprite(bg)
 	tmp.AddComponent(bg)
 }
 
 func (g *Game) unloadData() {
 	// Delete actors
 	for len(g.actors) > 0 {
-		g.actors[0].Destroy()
+		g.DestroyActor(g.actors[0])
 	}
 
 	// Destroy textures
@@ -199,6 +282,11 @@ func (g *Game) GetTexture(fileName string) *sdl.Texture {
 		return tex
 	}
 
+	// There is no renderer to create textures with
+	if g.headless {
+		return nil
+	}
+
 	// Load from file
 	surf, err := img.Load("chapter02/" + fileName)
 	if err != nil {
@@ -238,6 +326,18 @@ func (g *Game) Shutdown() (err error) {
 	return
 }
 
+func (g *Game) GetClock() Clock {
+	return g.clock
+}
+
+func (g *Game) IsRunning() bool {
+	return g.isRunning
+}
+
+func (g *Game) GetActors() []Actor {
+	return g.actors
+}
+
 func (g *Game) AddActor(actor Actor) {
 	// If we're updating actors, need to add to pending
 	if g.updatingActors {
@@ -245,9 +345,14 @@ func (g *Game) AddActor(actor Actor) {
 	} else {
 		g.actors = append(g.actors, actor)
 	}
+
+	actor.OnAdded()
 }
 
+// RemoveActor takes the actor out of the game without destroying it.
 func (g *Game) RemoveActor(actor Actor) {
+	count := len(g.pendingActors) + len(g.actors)
+
 	g.pendingActors = slices.DeleteFunc(g.pendingActors, func(a Actor) bool {
 		return a == actor
 	})
@@ -255,6 +360,38 @@ func (g *Game) RemoveActor(actor Actor) {
 	g.actors = slices.DeleteFunc(g.actors, func(a Actor) bool {
 		return a == actor
 	})
+
+	if len(g.pendingActors)+len(g.actors) < count {
+		actor.OnRemoved()
+	}
+}
+
+// DestroyActor removes the actor and all of its components from the game.
+func (g *Game) DestroyActor(actor Actor) {
+	actor.OnDestroy()
+
+	// Remove components, highest update order first
+	for components := actor.GetComponents(); len(components) > 0; components = actor.GetComponents() {
+		actor.RemoveComponent(components[len(components)-1])
+	}
+
+	g.RemoveActor(actor)
+}
+
+// addComponent registers a component the game keeps track of, such as a sprite
+func (g *Game) addComponent(c Component) {
+	switch c := c.(type) {
+	case Sprite:
+		g.AddSprite(c)
+	}
+}
+
+// removeComponent unregisters a component added with addComponent
+func (g *Game) removeComponent(c Component) {
+	switch c := c.(type) {
+	case Sprite:
+		g.RemoveSprite(c)
+	}
 }
 
 func (g *Game) AddSprite(s Sprite) {
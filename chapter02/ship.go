@@ -24,7 +24,6 @@ func NewShip(game *Game, drawOrder int) *Ship {
 		game.GetTexture("Assets/Ship04.png"),
 	}
 	asc.SetAnimTextures(anims)
-	game.AddSprite(asc)
 	s.Actor.AddComponent(asc)
 
 	return s
@@ -84,8 +83,3 @@ func (s *Ship) GetRightSpeed() float32 {
 func (s *Ship) GetDownSpeed() float32 {
 	return s.downSpeed
 }
-
-func (s *Ship) Destroy() {
-	s.GetGame().RemoveActor(s)
-	s.DestroyComponents()
-}
@@ -0,0 +1,77 @@
+package chapter03
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestGetComponent(t *testing.T) {
+	g := newHeadlessGame(t)
+	ast := g.GetAsteroids()[0]
+
+	circle, ok := GetComponent[CircleComponent](ast)
+	if !ok {
+		t.Fatalf("expected asteroid to have a circle component")
+	}
+	if circle.GetRadius() != 40 {
+		t.Errorf("expected radius 40, got %f", circle.GetRadius())
+	}
+
+	if !HasComponent[Sprite](ast) {
+		t.Errorf("expected asteroid to have a sprite")
+	}
+	if HasComponent[InputComponent](ast) {
+		t.Errorf("expected asteroid to have no input component")
+	}
+	if _, ok := GetComponent[InputComponent](g.GetShip()); !ok {
+		t.Errorf("expected ship to have an input component")
+	}
+
+	// An InputComponent is also a MoveComponent
+	if n := len(GetComponents[MoveComponent](g.GetShip())); n != 1 {
+		t.Errorf("expected 1 move component on the ship, got %d", n)
+	}
+	if n := len(GetComponents[Component](ast)); n != len(ast.GetComponents()) {
+		t.Errorf("expected every component, got %d of %d", n, len(ast.GetComponents()))
+	}
+}
+
+type hookComponent struct {
+	Component
+	calls []string
+}
+
+func (h *hookComponent) OnAdded()   { h.calls = append(h.calls, "added") }
+func (h *hookComponent) OnRemoved() { h.calls = append(h.calls, "removed") }
+func (h *hookComponent) OnEnable()  { h.calls = append(h.calls, "enable") }
+func (h *hookComponent) OnDisable() { h.calls = append(h.calls, "disable") }
+
+func TestDestroyActor(t *testing.T) {
+	g := newHeadlessGame(t)
+	ast := g.GetAsteroids()[0]
+	sprite, _ := GetComponent[Sprite](ast)
+
+	hc := &hookComponent{Component: NewComponent(ast, DefaultUpdateOrder)}
+	ast.AddComponent(hc)
+	ast.SetState(Paused)
+	ast.SetState(Active)
+
+	g.DestroyActor(ast)
+
+	want := []string{"added", "disable", "enable", "removed"}
+	if !slices.Equal(hc.calls, want) {
+		t.Errorf("expected hooks %v, got %v", want, hc.calls)
+	}
+	if len(ast.GetComponents()) != 0 {
+		t.Errorf("expected components to be removed, got %d", len(ast.GetComponents()))
+	}
+	if slices.Contains(g.sprites, sprite) {
+		t.Errorf("expected sprite to be removed from the game")
+	}
+	if slices.Contains(g.GetAsteroids(), ast) {
+		t.Errorf("expected asteroid to be removed from the game")
+	}
+	if slices.Contains(g.GetActors(), Actor(ast)) {
+		t.Errorf("expected actor to be removed from the game")
+	}
+}
@@ -7,7 +7,6 @@ import (
 
 type Asteroid struct {
 	Actor
-	circle CircleComponent
 }
 
 func NewAsteroid(game *Game, drawOrder int) *Asteroid {
@@ -15,40 +14,64 @@ func NewAsteroid(game *Game, drawOrder int) *Asteroid {
 		Actor: NewActor(game),
 	}
 
-	randPos := rand.GetVector2(math.ZeroVector2, math.Vector2{X: 1024, Y: 768})
+	// Spawns draw from their own stream, so they don't depend on other randomness
+	spawn := game.GetRNG().GetStream(rand.StreamSpawn)
+
+	randPos := spawn.GetVector2(math.ZeroVector2, game.GetWorldSize())
 	s.SetPosition(randPos)
 
-	randAngle := math.Angle(rand.GetFloatRange(0, math.TwoPi))
+	randAngle := math.Angle(spawn.GetFloatRange(0, math.TwoPi))
 	s.SetRotation(randAngle)
 
 	// create a sprite component
 	sc := NewSpriteComponent(s, drawOrder)
 	sc.SetTexture(game.GetTexture("Assets/Asteroid.png"))
-	game.AddSprite(sc)
 	s.AddComponent(sc)
 
-	// create a move component
-	mc := NewMoveComponent(s, DefaultUpdateOrder)
-	mc.SetForwardSpeed(150)
-	s.AddComponent(mc)
+	// create a rigid body, drifting forward
+	rb := NewRigidBodyComponent(s, DefaultUpdateOrder)
+	rb.SetVelocity(s.GetForward().MulScalar(150))
+	rb.SetRestitution(0.9)
+	s.AddComponent(rb)
 
-	// create a circle component
-	s.circle = NewCircleComponent(s, DefaultUpdateOrder)
-	s.circle.SetRadius(40)
-	s.AddComponent(s.circle)
+	// create a circle component, which bounces off other asteroids
+	cc := NewCircleComponent(s, DefaultUpdateOrder)
+	cc.SetRadius(40)
+	cc.SetLayer(LayerAsteroid)
+	cc.SetMask(LayerLaser | LayerAsteroid)
+	s.AddComponent(cc)
 
 	game.AddActor(s)
-	game.AddAsteroid(s)
 
 	return s
 }
 
-func (a *Asteroid) GetCircle() CircleComponent {
-	return a.circle
+func (a *Asteroid) OnAdded() {
+	a.GetGame().AddAsteroid(a)
 }
 
-func (a *Asteroid) Destroy() {
-	a.GetGame().RemoveActor(a)
+func (a *Asteroid) OnRemoved() {
 	a.GetGame().RemoveAsteroid(a)
-	a.DestroyComponents()
+}
+
+func (a *Asteroid) OnCollisionEnter(other CircleComponent) {
+	a.bounceOff(other)
+}
+
+// OnCollisionStay keeps pushing apart asteroids that are still overlapping
+func (a *Asteroid) OnCollisionStay(other CircleComponent) {
+	a.bounceOff(other)
+}
+
+func (a *Asteroid) bounceOff(other CircleComponent) {
+	if _, ok := other.GetOwner().(*Asteroid); !ok {
+		return
+	}
+
+	body, ok1 := GetComponent[RigidBodyComponent](a)
+	circle, ok2 := GetComponent[CircleComponent](a)
+	otherBody, ok3 := GetComponent[RigidBodyComponent](other.GetOwner())
+	if ok1 && ok2 && ok3 {
+		Bounce(body, otherBody, circle, other)
+	}
 }
@@ -0,0 +1,83 @@
+package chapter03
+
+import "github.com/ishtaka/go-game-programming/chapter03/math"
+
+// BoundsPolicy is what a moving component does with its owner when it leaves
+// the game's world (see Game.GetWorldSize).
+type BoundsPolicy int
+
+const (
+	// BoundsWrap moves it to the other side of the world
+	BoundsWrap BoundsPolicy = iota
+	// BoundsClamp stops it at the edge
+	BoundsClamp
+	// BoundsBounce stops it at the edge, and turns it back
+	BoundsBounce
+	// BoundsDestroy kills it
+	BoundsDestroy
+	// BoundsIgnore lets it go
+	BoundsIgnore
+)
+
+// moveInBounds moves owner to pos, applying policy if pos is outside the world.
+// It returns which edges it bounced off as a normal pointing back into the world,
+// or zero if it didn't bounce.
+func moveInBounds(owner Actor, pos math.Vector2, policy BoundsPolicy) math.Vector2 {
+	size := owner.GetGame().GetWorldSize()
+
+	// Which edges it's past, pointing back in
+	var normal math.Vector2
+	if pos.X < 0 {
+		normal.X = 1
+	} else if pos.X > size.X {
+		normal.X = -1
+	}
+	if pos.Y < 0 {
+		normal.Y = 1
+	} else if pos.Y > size.Y {
+		normal.Y = -1
+	}
+
+	if normal == math.ZeroVector2 || policy == BoundsIgnore {
+		owner.SetPosition(pos)
+		return math.ZeroVector2
+	}
+
+	switch policy {
+	case BoundsWrap:
+		pos.X += normal.X * size.X
+		pos.Y += normal.Y * size.Y
+		owner.SetPosition(pos)
+		// Don't interpolate across the screen
+		owner.SavePreviousTransform()
+	case BoundsClamp:
+		owner.SetPosition(clampToWorld(pos, size))
+	case BoundsBounce:
+		owner.SetPosition(clampToWorld(pos, size))
+		return normal
+	case BoundsDestroy:
+		owner.SetPosition(pos)
+		owner.SetState(Dead)
+	}
+
+	return math.ZeroVector2
+}
+
+func clampToWorld(pos, size math.Vector2) math.Vector2 {
+	return math.Vector2{
+		X: math.Clamp(pos.X, 0, size.X),
+		Y: math.Clamp(pos.Y, 0, size.Y),
+	}
+}
+
+// bounceOff turns v to point back into the world, along the edges in normal
+func bounceOff(v, normal math.Vector2) math.Vector2 {
+	if normal.X != 0 {
+		v.X = math.Abs(v.X) * normal.X
+	}
+	if normal.Y != 0 {
+		v.Y = math.Abs(v.Y) * normal.Y
+	}
+
+	return v
+}
@@ -0,0 +1,66 @@
+package chapter03
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/ishtaka/go-game-programming/chapter03/math"
+)
+
+func TestMoveComponentBounds(t *testing.T) {
+	tests := []struct {
+		policy BoundsPolicy
+		// Where the actor is after moving 20 past the right edge
+		want     math.Vector2
+		dead     bool
+		rotation math.Angle
+	}{
+		{BoundsWrap, math.Vector2{X: 10, Y: 100}, false, 0},
+		{BoundsClamp, math.Vector2{X: 200, Y: 100}, false, 0},
+		{BoundsBounce, math.Vector2{X: 200, Y: 100}, false, math.Angle(math.Pi)},
+		{BoundsDestroy, math.Vector2{X: 210, Y: 100}, true, 0},
+		{BoundsIgnore, math.Vector2{X: 210, Y: 100}, false, 0},
+	}
+
+	for _, tt := range tests {
+		g := newHeadlessGame(t)
+		g.SetWorldSize(math.Vector2{X: 200, Y: 150})
+
+		a := NewActor(g)
+		a.SetPosition(math.Vector2{X: 190, Y: 100})
+		mc := NewMoveComponent(a, DefaultUpdateOrder)
+		mc.SetForwardSpeed(20)
+		mc.SetBoundsPolicy(tt.policy)
+		a.AddComponent(mc)
+		g.AddActor(a)
+
+		g.Step(1)
+
+		if pos := a.GetPosition(); pos != tt.want {
+			t.Errorf("policy %d: expected %v, got %v", tt.policy, tt.want, pos)
+		}
+		if dead := a.GetState() == Dead || !slices.Contains(g.GetActors(), a); dead != tt.dead {
+			t.Errorf("policy %d: expected dead %v, got %v", tt.policy, tt.dead, dead)
+		}
+		if rot := a.GetRotation(); math.Abs(float32(rot-tt.rotation)) > 0.001 {
+			t.Errorf("policy %d: expected rotation %f, got %f", tt.policy, tt.rotation, rot)
+		}
+	}
+}
+
+func TestRigidBodyBouncesOffEdge(t *testing.T) {
+	g := newHeadlessGame(t)
+	a, rb := newBodyActor(g, math.Vector2{X: 100, Y: 5})
+	rb.SetVelocity(math.Vector2{X: 30, Y: -10})
+	rb.SetRestitution(0.5)
+	rb.SetBoundsPolicy(BoundsBounce)
+
+	g.Step(1)
+
+	if pos := a.GetPosition(); pos.Y != 0 {
+		t.Errorf("expected to stop at the top edge, got %v", pos)
+	}
+	if vel := rb.GetVelocity(); vel.X != 30 || vel.Y != 5 {
+		t.Errorf("expected velocity (30, 5), got %v", vel)
+	}
+}
@@ -0,0 +1,162 @@
+package broadphase
+
+import (
+	"cmp"
+	"slices"
+	"testing"
+
+	"github.com/ishtaka/go-game-programming/chapter03/math"
+	"github.com/ishtaka/go-game-programming/chapter03/math/rand"
+)
+
+type circle struct {
+	center math.Vector2
+	radius float32
+}
+
+func randomCircles(n int) []*circle {
+	s := rand.NewRNG(1).GetStream(rand.StreamDefault)
+
+	circles := make([]*circle, n)
+	for i := range circles {
+		circles[i] = &circle{
+			center: s.GetVector2(math.Vector2{X: -500, Y: -500}, math.Vector2{X: 1500, Y: 1000}),
+			radius: s.GetFloatRange(5, 60),
+		}
+	}
+
+	return circles
+}
+
+func TestQueryRadiusMatchesBruteForce(t *testing.T) {
+	circles := randomCircles(300)
+	g := NewGrid[*circle](64)
+	for _, c := range circles {
+		g.Insert(c, c.center, c.radius)
+	}
+
+	// Move some of them, and remove others
+	for i, c := range circles[:100] {
+		c.center = c.center.Add(math.Vector2{X: float32(i), Y: -float32(i)})
+		g.Update(c, c.center, c.radius)
+	}
+	for _, c := range circles[250:] {
+		g.Remove(c)
+	}
+	circles = circles[:250]
+	if g.Len() != 250 {
+		t.Fatalf("expected 250 items, got %d", g.Len())
+	}
+
+	for _, q := range randomCircles(20) {
+		var want []*circle
+		for _, c := range circles {
+			if intersect(c.center, c.radius, q.center, q.radius) {
+				want = append(want, c)
+			}
+		}
+
+		if got := g.QueryRadius(q.center, q.radius); !slices.Equal(got, want) {
+			t.Errorf("query at %v: expected %d items, got %d", q.center, len(want), len(got))
+		}
+	}
+}
+
+func TestNearest(t *testing.T) {
+	circles := randomCircles(200)
+	g := NewGrid[*circle](64)
+	for _, c := range circles {
+		g.Insert(c, c.center, c.radius)
+	}
+
+	// Only every other circle counts
+	filter := func(c *circle) bool {
+		return slices.Index(circles, c)%2 == 0
+	}
+
+	for _, q := range randomCircles(20) {
+		var want *circle
+		for i, c := range circles {
+			if i%2 == 0 && (want == nil || c.center.Sub(q.center).LengthSq() < want.center.Sub(q.center).LengthSq()) {
+				want = c
+			}
+		}
+
+		got, ok := g.Nearest(q.center, filter)
+		if !ok || got != want {
+			t.Errorf("nearest to %v: expected %v, got %v", q.center, want.center, got)
+		}
+	}
+
+	// Far outside every occupied cell
+	if got, ok := g.Nearest(math.Vector2{X: 100000, Y: 100000}, nil); !ok || got == nil {
+		t.Errorf("expected an item far from the grid")
+	}
+	if _, ok := NewGrid[*circle](64).Nearest(math.ZeroVector2, nil); ok {
+		t.Errorf("expected no item in an empty grid")
+	}
+}
+
+func TestNearestAfterRemove(t *testing.T) {
+	circles := randomCircles(200)
+	g := NewGrid[*circle](64)
+	for _, c := range circles {
+		g.Insert(c, c.center, c.radius)
+	}
+
+	// Remove the circles furthest out, so the bounds have to shrink
+	slices.SortFunc(circles, func(a, b *circle) int {
+		return cmp.Compare(a.center.LengthSq(), b.center.LengthSq())
+	})
+	for _, c := range circles[100:] {
+		g.Remove(c)
+	}
+	circles = circles[:100]
+
+	var lo, hi cell
+	first := true
+	for k := range g.cells {
+		if first {
+			lo, hi, first = k, k, false
+		}
+		lo = cell{min(lo.x, k.x), min(lo.y, k.y)}
+		hi = cell{max(hi.x, k.x), max(hi.y, k.y)}
+	}
+	if g.lo != lo || g.hi != hi {
+		t.Errorf("expected bounds %v to %v, got %v to %v", lo, hi, g.lo, g.hi)
+	}
+
+	for _, q := range randomCircles(20) {
+		var want *circle
+		for _, c := range circles {
+			if want == nil || c.center.Sub(q.center).LengthSq() < want.center.Sub(q.center).LengthSq() {
+				want = c
+			}
+		}
+
+		if got, ok := g.Nearest(q.center, nil); !ok || got != want {
+			t.Errorf("nearest to %v: expected %v, got %v", q.center, want.center, got)
+		}
+	}
+}
+
+func TestPairs(t *testing.T) {
+	circles := randomCircles(150)
+	g := NewGrid[*circle](64)
+	for _, c := range circles {
+		g.Insert(c, c.center, c.radius)
+	}
+
+	var want []Pair[*circle]
+	for i, a := range circles {
+		for _, b := range circles[i+1:] {
+			if intersect(a.center, a.radius, b.center, b.radius) {
+				want = append(want, Pair[*circle]{A: a, B: b})
+			}
+		}
+	}
+
+	if got := g.Pairs(); !slices.Equal(got, want) {
+		t.Errorf("expected %d pairs, got %d", len(want), len(got))
+	}
+}
@@ -7,17 +7,27 @@ type CircleComponent interface {
 	GetRadius() float32
 	SetRadius(radius float32)
 	GetCenter() math.Vector2
+	// GetLayer returns the layers the circle is on
+	GetLayer() CollisionLayer
+	SetLayer(layer CollisionLayer)
+	// GetMask returns the layers the circle collides with
+	GetMask() CollisionLayer
+	SetMask(mask CollisionLayer)
 }
 
 type circleComponent struct {
 	Component
 	radius float32
+	layer  CollisionLayer
+	mask   CollisionLayer
 }
 
 func NewCircleComponent(owner Actor, updateOrder int) CircleComponent {
 	c := NewComponent(owner, updateOrder)
 	cc := &circleComponent{
 		Component: c,
+		layer:     LayerDefault,
+		mask:      LayerAll,
 	}
 
 	return cc
@@ -35,8 +45,33 @@ func (c *circleComponent) GetCenter() math.Vector2 {
 	return c.GetOwner().GetPosition()
 }
 
-func (c *circleComponent) Destroy() {
-	c.GetOwner().RemoveComponent(c)
+func (c *circleComponent) GetLayer() CollisionLayer {
+	return c.layer
+}
+
+func (c *circleComponent) SetLayer(layer CollisionLayer) {
+	c.layer = layer
+}
+
+func (c *circleComponent) GetMask() CollisionLayer {
+	return c.mask
+}
+
+func (c *circleComponent) SetMask(mask CollisionLayer) {
+	c.mask = mask
+}
+
+// Update moves the circle in the game's collision grid to where the owner is now
+func (c *circleComponent) Update(deltaTime float32) {
+	c.GetOwner().GetGame().GetCollisionGrid().Update(c, c.GetCenter(), c.GetRadius())
+}
+
+func (c *circleComponent) OnAdded() {
+	c.GetOwner().GetGame().GetCollisionGrid().Insert(c, c.GetCenter(), c.GetRadius())
+}
+
+func (c *circleComponent) OnRemoved() {
+	c.GetOwner().GetGame().GetCollisionGrid().Remove(c)
 }
 
 func Intersect(a, b CircleComponent) bool {
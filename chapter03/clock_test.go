@@ -0,0 +1,50 @@
+package chapter03
+
+import (
+	"testing"
+	"time"
+)
+
+func TestManualClock(t *testing.T) {
+	c := NewManualClock()
+	if c.Now() != 0 {
+		t.Fatalf("expected clock to start at 0, got %s", c.Now())
+	}
+
+	c.Advance(16 * time.Millisecond)
+	c.Advance(time.Second)
+	if want := 1016 * time.Millisecond; c.Now() != want {
+		t.Errorf("expected %s, got %s", want, c.Now())
+	}
+}
+
+func TestScaledClock(t *testing.T) {
+	source := NewManualClock()
+	c := NewScaledClock(source)
+
+	source.Advance(time.Second)
+	if c.Now() != time.Second {
+		t.Fatalf("expected 1s at scale 1, got %s", c.Now())
+	}
+
+	// Half speed
+	c.SetScale(0.5)
+	source.Advance(time.Second)
+	if want := 1500 * time.Millisecond; c.Now() != want {
+		t.Errorf("expected %s at scale 0.5, got %s", want, c.Now())
+	}
+
+	// Paused
+	c.SetScale(0)
+	source.Advance(time.Second)
+	if want := 1500 * time.Millisecond; c.Now() != want {
+		t.Errorf("expected %s while paused, got %s", want, c.Now())
+	}
+
+	// Double speed
+	c.SetScale(2)
+	source.Advance(time.Second)
+	if want := 3500 * time.Millisecond; c.Now() != want {
+		t.Errorf("expected %s at scale 2, got %s", want, c.Now())
+	}
+}
@@ -0,0 +1,85 @@
+package chapter03
+
+import "github.com/ishtaka/go-game-programming/chapter03/broadphase"
+
+// CollisionLayer is a set of bits, one for each kind of thing that collides.
+type CollisionLayer uint32
+
+const (
+	LayerDefault CollisionLayer = 1 << iota
+	LayerShip
+	LayerLaser
+	LayerAsteroid
+)
+
+const (
+	LayerNone CollisionLayer = 0
+	LayerAll                 = ^LayerNone
+)
+
+// contact is two circles that touch, in the order the collision grid gives them
+type contact struct {
+	a, b CircleComponent
+}
+
+// CollisionSystem finds the circles that touch each update, and tells their
+// owners when they start touching (OnCollisionEnter), keep touching
+// (OnCollisionStay) and stop touching (OnCollisionExit).
+type CollisionSystem struct {
+	// Contacts found in the last update
+	contacts []contact
+}
+
+func NewCollisionSystem() *CollisionSystem {
+	return &CollisionSystem{}
+}
+
+// CanCollide reports whether a and b collide: whether either one's
+// mask has a layer the other is on.
+func CanCollide(a, b CircleComponent) bool {
+	return a.GetLayer()&b.GetMask() != 0 || b.GetLayer()&a.GetMask() != 0
+}
+
+// Update finds the circles in grid that touch, and calls the callbacks on
+// their owners. Actors that die in a callback get no more Enter or Stay
+// callbacks this update, so something destroyed on contact is only hit once.
+func (s *CollisionSystem) Update(grid *broadphase.Grid[CircleComponent]) {
+	prev := make(map[contact]bool, len(s.contacts))
+	for _, c := range s.contacts {
+		prev[c] = true
+	}
+
+	var contacts []contact
+	curr := make(map[contact]bool)
+	for _, p := range grid.Pairs() {
+		if !CanCollide(p.A, p.B) {
+			continue
+		}
+
+		c := contact{a: p.A, b: p.B}
+		contacts = append(contacts, c)
+		curr[c] = true
+
+		a, b := c.a.GetOwner(), c.b.GetOwner()
+		if a.GetState() == Dead || b.GetState() == Dead {
+			continue
+		}
+		if prev[c] {
+			a.OnCollisionStay(c.b)
+			b.OnCollisionStay(c.a)
+		} else {
+			a.OnCollisionEnter(c.b)
+			b.OnCollisionEnter(c.a)
+		}
+	}
+
+	// Contacts that ended, including ones where a circle was removed
+	for _, c := range s.contacts {
+		if !curr[c] {
+			c.a.GetOwner().OnCollisionExit(c.b)
+			c.b.GetOwner().OnCollisionExit(c.a)
+		}
+	}
+
+	s.contacts = contacts
+}
@@ -0,0 +1,125 @@
+package chapter03
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/ishtaka/go-game-programming/chapter03/math"
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+type contactActor struct {
+	Actor
+	events []string
+}
+
+func newContactActor(g *Game, pos math.Vector2, layer, mask CollisionLayer) *contactActor {
+	a := &contactActor{Actor: NewActor(g)}
+	a.SetPosition(pos)
+
+	cc := NewCircleComponent(a, DefaultUpdateOrder)
+	cc.SetRadius(10)
+	cc.SetLayer(layer)
+	cc.SetMask(mask)
+	a.AddComponent(cc)
+
+	g.AddActor(a)
+	return a
+}
+
+func (a *contactActor) OnCollisionEnter(other CircleComponent) {
+	a.events = append(a.events, "enter")
+}
+
+func (a *contactActor) OnCollisionStay(other CircleComponent) {
+	a.events = append(a.events, "stay")
+}
+
+func (a *contactActor) OnCollisionExit(other CircleComponent) {
+	a.events = append(a.events, "exit")
+}
+
+func TestCollisionCallbacks(t *testing.T) {
+	g := newHeadlessGame(t)
+	// Far from the asteroids, which don't collide with the default layer anyway
+	a := newContactActor(g, math.Vector2{X: -1000, Y: -1000}, LayerDefault, LayerAll)
+	b := newContactActor(g, math.Vector2{X: -990, Y: -1000}, LayerDefault, LayerNone)
+
+	g.Step(1.0 / 60.0)
+	g.Step(1.0 / 60.0)
+	b.SetPosition(math.Vector2{X: -900, Y: -1000})
+	g.Step(1.0 / 60.0)
+	g.Step(1.0 / 60.0)
+
+	// b's mask is empty, but a's mask has b's layer
+	want := []string{"enter", "stay", "exit"}
+	if !slices.Equal(a.events, want) {
+		t.Errorf("expected %v, got %v", want, a.events)
+	}
+	if !slices.Equal(b.events, want) {
+		t.Errorf("expected %v, got %v", want, b.events)
+	}
+
+	// Neither mask has the other's layer
+	c := newContactActor(g, math.Vector2{X: -1000, Y: -2000}, LayerLaser, LayerLaser)
+	d := newContactActor(g, math.Vector2{X: -1000, Y: -2000}, LayerAsteroid, LayerAsteroid)
+	g.Step(1.0 / 60.0)
+	if len(c.events) != 0 || len(d.events) != 0 {
+		t.Errorf("expected no collision between masked out layers")
+	}
+}
+
+func TestLaserDestroysOneAsteroid(t *testing.T) {
+	g := newHeadlessGame(t)
+	for _, ast := range g.GetAsteroids() {
+		ast.SetState(Dead)
+	}
+	g.Step(1.0 / 60.0)
+
+	// Two asteroids where the laser appears
+	pos := math.Vector2{X: 100, Y: 100}
+	for range 2 {
+		ast := NewAsteroid(g, DefaultDrawOrder)
+		ast.SetPosition(pos)
+		body, _ := GetComponent[RigidBodyComponent](ast)
+		body.SetVelocity(math.ZeroVector2)
+	}
+	laser := NewLaser(g, DefaultDrawOrder)
+	laser.SetPosition(pos)
+	g.Step(1.0 / 60.0)
+	g.Step(1.0 / 60.0)
+
+	if slices.Contains(g.GetActors(), Actor(laser)) {
+		t.Errorf("expected the laser to be destroyed")
+	}
+	if n := len(g.GetAsteroids()); n != 1 {
+		t.Errorf("expected 1 asteroid left, got %d", n)
+	}
+}
+
+func TestFiredLaserStartsAtShip(t *testing.T) {
+	g := newHeadlessGame(t)
+	for _, ast := range g.GetAsteroids() {
+		ast.SetState(Dead)
+	}
+	g.Step(1.0 / 60.0)
+
+	// An asteroid sitting at the origin, far from the ship
+	ast := NewAsteroid(g, DefaultDrawOrder)
+	ast.SetPosition(math.ZeroVector2)
+	body, _ := GetComponent[RigidBodyComponent](ast)
+	body.SetVelocity(math.ZeroVector2)
+	g.Step(1.0 / 60.0)
+
+	// Fire, which adds the laser while actors are handling input
+	var state InputState
+	state.Keyboard.currState[sdl.SCANCODE_SPACE] = 1
+	g.inputSystem.SetState(&state)
+	g.handleInput()
+	g.Step(1.0 / 60.0)
+	g.Step(1.0 / 60.0)
+
+	if !slices.Contains(g.GetAsteroids(), ast) {
+		t.Errorf("expected a laser fired from the ship not to hit an asteroid at the origin")
+	}
+}
@@ -2,17 +2,53 @@ package chapter03
 
 import (
 	"slices"
+	"time"
 
+	"github.com/ishtaka/go-game-programming/chapter03/broadphase"
 	"github.com/ishtaka/go-game-programming/chapter03/math"
+	"github.com/ishtaka/go-game-programming/chapter03/math/rand"
 	"github.com/veandco/go-sdl2/img"
 	"github.com/veandco/go-sdl2/sdl"
 )
 
+// DefaultTickRate is the number of simulation steps per second.
+const DefaultTickRate = 60
+
+// collisionCellSize is the size of the grid cells circles are sorted into.
+const collisionCellSize = 128
+
+// maxFrameTime is the most time simulated in a single frame (in seconds).
+const maxFrameTime = 0.25
+
+// Size of the window, and of the world unless it's changed with SetWorldSize
+const (
+	screenWidth  = 1024
+	screenHeight = 768
+)
+
 type Game struct {
-	window     *sdl.Window
-	renderer   *sdl.Renderer
-	ticksCount uint64
-	isRunning  bool
+	window      *sdl.Window
+	renderer    *sdl.Renderer
+	inputSystem *InputSystem
+	inputMap    *InputMap
+	rng         *rand.RNG
+	clock       Clock
+	lastTime    time.Duration
+	isRunning   bool
+	// Run the simulation without a window or renderer
+	headless bool
+
+	// Time between simulation steps (in seconds)
+	fixedDeltaTime float32
+	// Time not yet simulated
+	accumulator float32
+	// Fraction of a step to interpolate drawing by
+	alpha float32
+
+	// Input being recorded, or played back in place of real input
+	recording   *Recording
+	replay      *Recording
+	replayIndex int
 
 	textures map[string]*sdl.Texture
 	sprites  []Sprite
@@ -21,19 +57,48 @@ type Game struct {
 	pendingActors  []Actor
 	updatingActors bool
 
+	// Size of the area actors move in, from (0, 0)
+	worldSize math.Vector2
+
+	// Circle components sorted by position, for collision queries
+	collisions      *broadphase.Grid[CircleComponent]
+	collisionSystem *CollisionSystem
+
 	ship      *Ship
 	asteroids []*Asteroid
 }
 
-func NewGame() *Game {
+func NewGame(clock Clock) *Game {
 	return &Game{
-		ticksCount: 0,
-		textures:   make(map[string]*sdl.Texture),
-		isRunning:  true,
+		clock:           clock,
+		rng:             rand.NewRNG(uint64(time.Now().UnixNano())),
+		inputSystem:     NewInputSystem(),
+		fixedDeltaTime:  1.0 / DefaultTickRate,
+		worldSize:       math.Vector2{X: screenWidth, Y: screenHeight},
+		collisions:      broadphase.NewGrid[CircleComponent](collisionCellSize),
+		collisionSystem: NewCollisionSystem(),
+		textures:        make(map[string]*sdl.Texture),
+		isRunning:       true,
 	}
 }
 
+// NewHeadlessGame creates a game that simulates without a window or renderer.
+// Use Step to advance the simulation.
+func NewHeadlessGame(clock Clock) *Game {
+	g := NewGame(clock)
+	g.headless = true
+
+	return g
+}
+
 func (g *Game) Initialize() error {
+	if g.headless {
+		g.loadData()
+		g.savePreviousTransforms()
+		g.lastTime = g.clock.Now()
+		return nil
+	}
+
 	if err := sdl.Init(sdl.INIT_EVERYTHING); err != nil {
 		sdl.Log("unable to initialize SDL: %s\n", err)
 		return err
@@ -41,7 +106,7 @@ func (g *Game) Initialize() error {
 
 	var err error
 
-	g.window, err = sdl.CreateWindow("Chapter 3", sdl.WINDOWPOS_UNDEFINED, sdl.WINDOWPOS_UNDEFINED, 1024, 768, sdl.WINDOW_SHOWN)
+	g.window, err = sdl.CreateWindow("Chapter 3", sdl.WINDOWPOS_UNDEFINED, sdl.WINDOWPOS_UNDEFINED, screenWidth, screenHeight, sdl.WINDOW_SHOWN)
 	if err != nil {
 		sdl.Log("failed to create window: %s\n", err)
 		return err
@@ -59,67 +124,173 @@ func (g *Game) Initialize() error {
 		return err
 	}
 
+	// Initialize input system
+	if err = g.inputSystem.Initialize(); err != nil {
+		sdl.Log("failed to initialize input system: %s\n", err)
+		return err
+	}
+
 	g.loadData()
+	g.savePreviousTransforms()
 
-	g.ticksCount = sdl.GetTicks64()
+	g.lastTime = g.clock.Now()
 
 	return nil
 }
 
 func (g *Game) RunLoop() {
 	for g.isRunning {
-		g.processInput()
+		g.RunFrame()
+	}
+}
+
+// RunFrame runs a single iteration of the game loop, simulating
+// the time passed on the clock since the previous frame.
+func (g *Game) RunFrame() {
+	if g.replay != nil {
+		g.replayFrame()
+	} else {
+		if !g.headless {
+			g.processInput()
+		}
 		g.update()
+	}
+	if !g.headless {
 		g.generateOutput()
 	}
 }
 
+// Step advances the simulation by deltaTime seconds,
+// without reading input or drawing.
+func (g *Game) Step(deltaTime float32) {
+	g.updateGame(deltaTime)
+}
+
+// SetTickRate sets the number of simulation steps per second.
+func (g *Game) SetTickRate(ticksPerSecond float32) {
+	g.fixedDeltaTime = 1.0 / ticksPerSecond
+}
+
+// GetTickRate returns the number of simulation steps per second.
+func (g *Game) GetTickRate() float32 {
+	return 1.0 / g.fixedDeltaTime
+}
+
 func (g *Game) processInput() {
+	g.inputSystem.PrepareForUpdate()
+
 	for event := sdl.PollEvent(); event != nil; event = sdl.PollEvent() {
 		switch event.(type) {
 		case *sdl.QuitEvent:
 			g.isRunning = false
-			return
+		default:
+			g.inputSystem.ProcessEvent(event)
 		}
+	}
 
-		state := sdl.GetKeyboardState()
-		if state[sdl.SCANCODE_ESCAPE] != 0 {
-			g.isRunning = false
-			return
-		}
+	g.inputSystem.Update()
+	g.handleInput()
+}
 
-		g.updatingActors = true
-		for _, a := range g.actors {
-			a.ProcessInput(state)
-		}
-		g.updatingActors = false
+func (g *Game) handleInput() {
+	state := g.inputSystem.GetState()
+
+	if state.Keyboard.GetKeyValue(sdl.SCANCODE_ESCAPE) {
+		g.isRunning = false
+		return
+	}
+
+	g.updatingActors = true
+	for _, a := range g.actors {
+		a.ProcessInput(state)
 	}
+	g.updatingActors = false
 }
 
 func (g *Game) update() {
-	// SDL_TICKS_PASSED is not available in go-sdl2
-	for {
-		if sdl.GetTicks64() > g.ticksCount+16 {
-			break
+	// Time elapsed since last frame (converted to seconds)
+	now := g.clock.Now()
+	frameTime := float32((now - g.lastTime).Seconds())
+	g.lastTime = now
+
+	if g.recording != nil {
+		g.recording.AddFrame(frameTime, g.inputSystem.GetState())
+	}
+
+	g.simulate(frameTime)
+}
+
+// simulate runs as many fixed steps as fit in frameTime,
+// and any time left over is carried to the next frame.
+func (g *Game) simulate(frameTime float32) {
+	// Clamp the frame time, so a slow frame can't demand more and more
+	// steps to catch up (spiral of death)
+	if frameTime > maxFrameTime {
+		frameTime = maxFrameTime
+	}
+
+	// Simulate in fixed steps until the accumulated time is used up
+	g.accumulator += frameTime
+	for g.accumulator >= g.fixedDeltaTime {
+		g.updateGame(g.fixedDeltaTime)
+		g.accumulator -= g.fixedDeltaTime
+	}
+
+	// How far we are between the last step and the next one
+	g.alpha = g.accumulator / g.fixedDeltaTime
+}
+
+// replayFrame plays the next frame of the replay,
+// using its input and frame time instead of real ones.
+func (g *Game) replayFrame() {
+	if !g.headless {
+		// Keep the window responsive
+		for event := sdl.PollEvent(); event != nil; event = sdl.PollEvent() {
+			if _, ok := event.(*sdl.QuitEvent); ok {
+				g.isRunning = false
+			}
 		}
 	}
 
-	deltaTime := float32(sdl.GetTicks64()-g.ticksCount) / 1000.0
-	if deltaTime > 0.05 {
-		deltaTime = 0.05
+	if g.replayIndex >= len(g.replay.Frames) {
+		g.isRunning = false
+		return
 	}
+	frame := &g.replay.Frames[g.replayIndex]
+	g.replayIndex++
 
-	g.ticksCount = sdl.GetTicks64()
+	g.inputSystem.PrepareForUpdate()
+	g.inputSystem.SetState(&frame.Input)
+	g.handleInput()
+
+	g.simulate(frame.FrameTime)
+}
+
+func (g *Game) updateGame(deltaTime float32) {
+	// Keep transforms from before this update to interpolate drawing from
+	g.savePreviousTransforms()
 
 	// Update all actors
 	g.updatingActors = true
 	for _, a := range g.actors {
 		a.Update(deltaTime)
 	}
+
+	// Pending actors don't update, so their circles are still wherever
+	// they were when they were added. Move them to where they are now.
+	for _, pending := range g.pendingActors {
+		if cc, ok := GetComponent[CircleComponent](pending); ok {
+			g.collisions.Update(cc, cc.GetCenter(), cc.GetRadius())
+		}
+	}
+
+	// Let actors know about what they've run into
+	g.collisionSystem.Update(g.collisions)
 	g.updatingActors = false
 
 	// Move any pending actors to actors
 	for _, pending := range g.pendingActors {
+		pending.SavePreviousTransform()
 		g.actors = append(g.actors, pending)
 	}
 	g.pendingActors = nil
@@ -133,7 +304,13 @@ func (g *Game) update() {
 	}
 	// Delete dead actors (which removes them from actors)
 	for _, deadActor := range deadActors {
-		deadActor.Destroy()
+		g.DestroyActor(deadActor)
+	}
+}
+
+func (g *Game) savePreviousTransforms() {
+	for _, a := range g.actors {
+		a.SavePreviousTransform()
 	}
 }
 
@@ -145,16 +322,22 @@ func (g *Game) generateOutput() {
 
 	// Draw all sprite components
 	for _, sprite := range g.sprites {
-		sprite.Draw(g.renderer)
+		sprite.Draw(g.renderer, g.alpha)
 	}
 
 	g.renderer.Present()
 }
 
 func (g *Game) loadData() {
+	// Use the default controls, unless they've been remapped
+	g.inputMap = NewDefaultInputMap()
+	if !g.headless {
+		g.inputMap.Load("Assets/Controls.json")
+	}
+
 	// Create player's ship
 	g.ship = NewShip(g, DefaultDrawOrder)
-	g.ship.SetPosition(math.Vector2{X: 512, Y: 384})
+	g.ship.SetPosition(g.worldSize.MulScalar(0.5))
 	g.ship.SetRotation(math.Angle(math.PiOver2))
 
 	// Create asteroids
@@ -167,7 +350,7 @@ func (g *Game) loadData() {
 func (g *Game) unloadData() {
 	// Delete actors
 	for len(g.actors) > 0 {
-		g.actors[0].Destroy()
+		g.DestroyActor(g.actors[0])
 	}
 
 	// Destroy textures
@@ -182,6 +365,11 @@ func (g *Game) GetTexture(fileName string) *sdl.Texture {
 		return tex
 	}
 
+	// There is no renderer to create textures with
+	if g.headless {
+		return nil
+	}
+
 	// Load from file
 	surf, err := img.Load("chapter03/" + fileName)
 	if err != nil {
@@ -204,6 +392,11 @@ func (g *Game) GetTexture(fileName string) *sdl.Texture {
 
 func (g *Game) Shutdown() (err error) {
 	defer sdl.Quit()
+	defer func() {
+		if g.inputSystem != nil {
+			g.inputSystem.Shutdown()
+		}
+	}()
 	defer func() {
 		if g.window != nil {
 			err = g.window.Destroy()
@@ -221,6 +414,20 @@ func (g *Game) Shutdown() (err error) {
 	return
 }
 
+// GetWorldSize returns the size of the area actors move in.
+func (g *Game) GetWorldSize() math.Vector2 {
+	return g.worldSize
+}
+
+// SetWorldSize sets the size of the area actors move in, from (0, 0).
+func (g *Game) SetWorldSize(size math.Vector2) {
+	g.worldSize = size
+}
+
+func (g *Game) GetShip() *Ship {
+	return g.ship
+}
+
 func (g *Game) GetAsteroids() []*Asteroid {
 	return g.asteroids
 }
@@ -235,6 +442,60 @@ func (g *Game) RemoveAsteroid(ast *Asteroid) {
 	})
 }
 
+func (g *Game) GetInputMap() *InputMap {
+	return g.inputMap
+}
+
+// StartRecording records the input of every frame from now on.
+// Call it before Initialize, so the recording starts with the game.
+func (g *Game) StartRecording(seed uint64) {
+	g.rng.Seed(seed)
+	g.recording = NewRecording(seed, g.GetTickRate())
+}
+
+// StopRecording stops recording and returns what was recorded.
+func (g *Game) StopRecording() *Recording {
+	rec := g.recording
+	g.recording = nil
+	return rec
+}
+
+// StartReplay plays a recording back in place of input and the clock,
+// until it runs out of frames. Call it before Initialize.
+func (g *Game) StartReplay(rec *Recording) {
+	g.rng.Seed(rec.Seed)
+	g.SetTickRate(rec.TickRate)
+	g.replay = rec
+	g.replayIndex = 0
+}
+
+func (g *Game) IsReplaying() bool {
+	return g.replay != nil && g.replayIndex < len(g.replay.Frames)
+}
+
+// GetRNG returns the game's random number generator.
+// Seed it before Initialize to make the game repeatable.
+func (g *Game) GetRNG() *rand.RNG {
+	return g.rng
+}
+
+func (g *Game) GetClock() Clock {
+	return g.clock
+}
+
+func (g *Game) IsRunning() bool {
+	return g.isRunning
+}
+
+func (g *Game) GetActors() []Actor {
+	return g.actors
+}
+
+// GetCollisionGrid returns the circle components in the game, sorted by position.
+func (g *Game) GetCollisionGrid() *broadphase.Grid[CircleComponent] {
+	return g.collisions
+}
+
 func (g *Game) AddActor(actor Actor) {
 	// If we're updating actors, need to add to pending
 	if g.updatingActors {
@@ -242,9 +503,14 @@ func (g *Game) AddActor(actor Actor) {
 	} else {
 		g.actors = append(g.actors, actor)
 	}
+
+	actor.OnAdded()
 }
 
+// RemoveActor takes the actor out of the game without destroying it.
 func (g *Game) RemoveActor(actor Actor) {
+	count := len(g.pendingActors) + len(g.actors)
+
 	g.pendingActors = slices.DeleteFunc(g.pendingActors, func(a Actor) bool {
 		return a == actor
 	})
@@ -252,6 +518,38 @@ func (g *Game) RemoveActor(actor Actor) {
 	g.actors = slices.DeleteFunc(g.actors, func(a Actor) bool {
 		return a == actor
 	})
+
+	if len(g.pendingActors)+len(g.actors) < count {
+		actor.OnRemoved()
+	}
+}
+
+// DestroyActor removes the actor and all of its components from the game.
+func (g *Game) DestroyActor(actor Actor) {
+	actor.OnDestroy()
+
+	// Remove components, highest update order first
+	for components := actor.GetComponents(); len(components) > 0; components = actor.GetComponents() {
+		actor.RemoveComponent(components[len(components)-1])
+	}
+
+	g.RemoveActor(actor)
+}
+
+// addComponent registers a component the game keeps track of, such as a sprite
+func (g *Game) addComponent(c Component) {
+	switch c := c.(type) {
+	case Sprite:
+		g.AddSprite(c)
+	}
+}
+
+// removeComponent unregisters a component added with addComponent
+func (g *Game) removeComponent(c Component) {
+	switch c := c.(type) {
+	case Sprite:
+		g.RemoveSprite(c)
+	}
 }
 
 func (g *Game) AddSprite(s Sprite) {
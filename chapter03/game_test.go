@@ -0,0 +1,79 @@
+package chapter03
+
+import (
+	"testing"
+	"time"
+)
+
+func newHeadlessGame(t *testing.T) *Game {
+	t.Helper()
+
+	g := NewHeadlessGame(NewManualClock())
+	if err := g.Initialize(); err != nil {
+		t.Fatalf("failed to initialize headless game: %s", err)
+	}
+	t.Cleanup(func() {
+		_ = g.Shutdown()
+	})
+
+	return g
+}
+
+func TestHeadlessAsteroids(t *testing.T) {
+	g := newHeadlessGame(t)
+
+	if n := len(g.GetAsteroids()); n != 20 {
+		t.Fatalf("expected 20 asteroids, got %d", n)
+	}
+
+	const deltaTime = 1.0 / 60.0
+	for range 3000 {
+		g.Step(deltaTime)
+	}
+
+	if n := len(g.GetAsteroids()); n != 20 {
+		t.Errorf("expected 20 asteroids, got %d", n)
+	}
+
+	for _, ast := range g.GetAsteroids() {
+		pos := ast.GetPosition()
+		if pos.X < 0 || pos.X > 1024 || pos.Y < 0 || pos.Y > 768 {
+			t.Errorf("asteroid left the screen: %v", pos)
+		}
+	}
+
+	if pos := g.GetShip().GetPosition(); pos.X != 512 || pos.Y != 384 {
+		t.Errorf("ship moved without input: %v", pos)
+	}
+}
+
+func TestRunFrameFollowsClock(t *testing.T) {
+	clock := NewManualClock()
+	g := NewHeadlessGame(clock)
+	if err := g.Initialize(); err != nil {
+		t.Fatalf("failed to initialize headless game: %s", err)
+	}
+	t.Cleanup(func() {
+		_ = g.Shutdown()
+	})
+
+	// Watch an asteroid to see whether the simulation stepped
+	ast := g.GetAsteroids()[0]
+	start := ast.GetPosition()
+
+	// No time has passed, so nothing is simulated
+	g.RunFrame()
+	if pos := ast.GetPosition(); pos != start {
+		t.Fatalf("expected no movement without time passing, moved to %v", pos)
+	}
+
+	// A tenth of a second is several steps at the default tick rate
+	clock.Advance(100 * time.Millisecond)
+	g.RunFrame()
+	if g.accumulator >= g.fixedDeltaTime {
+		t.Errorf("expected the accumulator to be drained, got %f", g.accumulator)
+	}
+	if pos := ast.GetPosition(); pos == start {
+		t.Errorf("expected asteroid to move after the clock advanced")
+	}
+}
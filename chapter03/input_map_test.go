@@ -0,0 +1,109 @@
+package chapter03
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+func TestInputMapAxis(t *testing.T) {
+	m := NewDefaultInputMap()
+
+	var state InputState
+	if v := m.GetAxisValue(&state, "Thrust"); v != 0 {
+		t.Errorf("expected no thrust, got %f", v)
+	}
+
+	state.Keyboard.currState[sdl.SCANCODE_W] = 1
+	if v := m.GetAxisValue(&state, "Thrust"); v != 1 {
+		t.Errorf("expected full thrust, got %f", v)
+	}
+
+	// Key and stick together are still clamped
+	state.Controller.leftStick.Y = 0.5
+	if v := m.GetAxisValue(&state, "Thrust"); v != 1 {
+		t.Errorf("expected thrust clamped to 1, got %f", v)
+	}
+
+	// Turn uses an inverted stick
+	state.Controller.leftStick.X = 0.5
+	if v := m.GetAxisValue(&state, "Turn"); v != -0.5 {
+		t.Errorf("expected turn of -0.5, got %f", v)
+	}
+
+	if v := m.GetAxisValue(&state, "Missing"); v != 0 {
+		t.Errorf("expected unbound axis to be 0, got %f", v)
+	}
+}
+
+func TestInputMapAction(t *testing.T) {
+	m := NewDefaultInputMap()
+
+	var state InputState
+	state.Keyboard.currState[sdl.SCANCODE_SPACE] = 1
+	if s := m.GetActionState(&state, "Fire"); s != Pressed {
+		t.Errorf("expected fire pressed, got %d", s)
+	}
+
+	// Switching from the key to the controller button keeps the action held
+	state.Keyboard.prevState = state.Keyboard.currState
+	state.Keyboard.currState[sdl.SCANCODE_SPACE] = 0
+	state.Controller.currButtons[sdl.CONTROLLER_BUTTON_A] = 1
+	if s := m.GetActionState(&state, "Fire"); s != Held {
+		t.Errorf("expected fire held, got %d", s)
+	}
+
+	// Rebind fire to the left mouse button
+	if err := m.BindAction("Fire", Binding{MouseButton: sdl.BUTTON_LEFT}); err != nil {
+		t.Fatalf("failed to rebind: %s", err)
+	}
+	if m.GetActionValue(&state, "Fire") {
+		t.Errorf("expected old bindings to be removed")
+	}
+	state.Mouse.currButtons = sdl.Button(sdl.BUTTON_LEFT)
+	if !m.GetActionValue(&state, "Fire") {
+		t.Errorf("expected fire from the mouse")
+	}
+}
+
+func TestInputMapJSON(t *testing.T) {
+	m := NewDefaultInputMap()
+
+	data := []byte(`{"actions": {"Fire": [{"key": "Return"}]}}`)
+	if err := json.Unmarshal(data, m); err != nil {
+		t.Fatalf("failed to load bindings: %s", err)
+	}
+
+	if b := m.GetActionBindings("Fire"); len(b) != 1 || b[0].Key != "Return" {
+		t.Errorf("expected fire to be rebound to Return, got %v", b)
+	}
+	// Axes not in the file keep the defaults
+	if b := m.GetAxisBindings("Thrust"); len(b) != 2 {
+		t.Errorf("expected default thrust bindings, got %v", b)
+	}
+
+	// Saved bindings load back the same
+	saved, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("failed to save bindings: %s", err)
+	}
+	loaded := NewInputMap()
+	if err := json.Unmarshal(saved, loaded); err != nil {
+		t.Fatalf("failed to load saved bindings: %s", err)
+	}
+	var state InputState
+	state.Keyboard.currState[sdl.SCANCODE_RETURN] = 1
+	if !loaded.GetActionValue(&state, "Fire") {
+		t.Errorf("expected saved binding to fire on Return")
+	}
+
+	// Invalid bindings leave the map unchanged
+	data = []byte(`{"actions": {"Fire": [{"key": "NotAKey"}]}}`)
+	if err := json.Unmarshal(data, m); err == nil {
+		t.Errorf("expected an error for an unknown key")
+	}
+	if b := m.GetActionBindings("Fire"); len(b) != 1 || b[0].Key != "Return" {
+		t.Errorf("expected bindings to be unchanged, got %v", b)
+	}
+}
@@ -0,0 +1,73 @@
+package chapter03
+
+import (
+	"testing"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+func TestKeyboardState(t *testing.T) {
+	var k KeyboardState
+
+	frames := []struct {
+		down bool
+		want ButtonState
+	}{
+		{false, None},
+		{true, Pressed},
+		{true, Held},
+		{false, Released},
+		{false, None},
+	}
+
+	for i, f := range frames {
+		k.prevState = k.currState
+		k.currState[sdl.SCANCODE_SPACE] = 0
+		if f.down {
+			k.currState[sdl.SCANCODE_SPACE] = 1
+		}
+
+		if got := k.GetKeyState(sdl.SCANCODE_SPACE); got != f.want {
+			t.Errorf("frame %d: expected %d, got %d", i, f.want, got)
+		}
+		if got := k.GetKeyValue(sdl.SCANCODE_SPACE); got != f.down {
+			t.Errorf("frame %d: expected key value %t, got %t", i, f.down, got)
+		}
+	}
+}
+
+func TestMouseButtonState(t *testing.T) {
+	m := MouseState{
+		prevButtons: sdl.Button(sdl.BUTTON_LEFT),
+		currButtons: sdl.Button(sdl.BUTTON_LEFT) | sdl.Button(sdl.BUTTON_RIGHT),
+	}
+
+	if got := m.GetButtonState(sdl.BUTTON_LEFT); got != Held {
+		t.Errorf("expected left button held, got %d", got)
+	}
+	if got := m.GetButtonState(sdl.BUTTON_RIGHT); got != Pressed {
+		t.Errorf("expected right button pressed, got %d", got)
+	}
+	if m.GetButtonValue(sdl.BUTTON_MIDDLE) {
+		t.Errorf("expected middle button up")
+	}
+}
+
+func TestFilter(t *testing.T) {
+	if f := filter1D(100); f != 0 {
+		t.Errorf("expected trigger in dead zone to be 0, got %f", f)
+	}
+	if f := filter1D(32767); f != 1 {
+		t.Errorf("expected full trigger to be 1, got %f", f)
+	}
+
+	if v := filter2D(4000, 4000); v.X != 0 || v.Y != 0 {
+		t.Errorf("expected stick in dead zone to be 0, got %v", v)
+	}
+
+	// Pushed fully down, which is negative y
+	v := filter2D(0, 32767)
+	if v.X != 0 || v.Y != -1 {
+		t.Errorf("expected stick down to be (0, -1), got %v", v)
+	}
+}
@@ -0,0 +1,110 @@
+package math
+
+import "testing"
+
+func TestIntersect(t *testing.T) {
+	square := AABB{Min: Vector2{0, 0}, Max: Vector2{10, 10}}
+	// A diamond around (20, 5), which only reaches x = 15
+	diamond := OBB{Center: Vector2{20, 5}, HalfExtents: Vector2{3.5, 3.5}, Rotation: Angle(Pi / 4)}
+	triangle := ConvexPolygon{Vertices: []Vector2{{12, 0}, {16, 0}, {12, 4}}}
+
+	tests := []struct {
+		name string
+		a, b Shape
+		want bool
+	}{
+		{"circle circle", Circle{Vector2{0, 0}, 5}, Circle{Vector2{9, 0}, 5}, true},
+		{"circle circle apart", Circle{Vector2{0, 0}, 5}, Circle{Vector2{11, 0}, 5}, false},
+		{"circle box corner", Circle{Vector2{13, 13}, 4}, square, false},
+		{"circle box edge", square, Circle{Vector2{13, 5}, 4}, true},
+		{"circle inside box", Circle{Vector2{5, 5}, 1}, square, true},
+		{"circle obb", Circle{Vector2{13, 5}, 1.5}, diamond, false},
+		{"circle obb touching", Circle{Vector2{14, 5}, 1.5}, diamond, true},
+		{"circle segment", Circle{Vector2{5, 5}, 2}, LineSegment{Vector2{0, 6}, Vector2{10, 6}}, true},
+		{"circle polygon", Circle{Vector2{14, 3}, 1}, triangle, true},
+		{"box box", square, AABB{Vector2{10, 10}, Vector2{20, 20}}, true},
+		{"box box apart", square, AABB{Vector2{11, 0}, Vector2{20, 20}}, false},
+		{"box obb", square, diamond, false},
+		{"box obb overlap", AABB{Vector2{0, 0}, Vector2{16, 10}}, diamond, true},
+		{"box segment through", square, LineSegment{Vector2{-5, 5}, Vector2{15, 5}}, true},
+		{"box segment past corner", square, LineSegment{Vector2{9, 13}, Vector2{13, 9}}, false},
+		{"box segment inside", square, LineSegment{Vector2{2, 2}, Vector2{3, 3}}, true},
+		{"box polygon", square, triangle, false},
+		{"obb segment", diamond, LineSegment{Vector2{20, -5}, Vector2{20, 15}}, true},
+		{"obb polygon", diamond, triangle, false},
+		{"segment segment crossing", LineSegment{Vector2{0, 0}, Vector2{10, 10}}, LineSegment{Vector2{0, 10}, Vector2{10, 0}}, true},
+		{"segment segment apart", LineSegment{Vector2{0, 0}, Vector2{4, 4}}, LineSegment{Vector2{0, 10}, Vector2{10, 0}}, false},
+		{"segment segment collinear", LineSegment{Vector2{0, 0}, Vector2{4, 0}}, LineSegment{Vector2{6, 0}, Vector2{8, 0}}, false},
+		{"segment polygon", LineSegment{Vector2{11, 1}, Vector2{13, 1}}, triangle, true},
+		{"polygon polygon", triangle, ConvexPolygon{Vertices: []Vector2{{15, 2}, {17, 2}, {16, 4}}}, false},
+		{"circle empty polygon", Circle{Vector2{0, 0}, 5}, ConvexPolygon{}, false},
+		{"box empty polygon", square, ConvexPolygon{}, false},
+	}
+
+	for _, tt := range tests {
+		if got := Intersect(tt.a, tt.b); got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+		if got := Intersect(tt.b, tt.a); got != tt.want {
+			t.Errorf("%s (swapped): expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func nearlyEqual(a, b Vector2) bool {
+	return NearZero(a.Sub(b).LengthSq())
+}
+
+func TestSegmentCast(t *testing.T) {
+	tests := []struct {
+		name   string
+		seg    LineSegment
+		shape  Shape
+		want   bool
+		point  Vector2
+		normal Vector2
+	}{
+		{"circle", LineSegment{Vector2{-10, 0}, Vector2{10, 0}}, Circle{Vector2{0, 0}, 2}, true, Vector2{-2, 0}, Vector2{-1, 0}},
+		{"circle short", LineSegment{Vector2{-10, 0}, Vector2{-5, 0}}, Circle{Vector2{0, 0}, 2}, false, Vector2{}, Vector2{}},
+		{"circle behind", LineSegment{Vector2{5, 0}, Vector2{10, 0}}, Circle{Vector2{0, 0}, 2}, false, Vector2{}, Vector2{}},
+		{"box", LineSegment{Vector2{5, 20}, Vector2{5, -20}}, AABB{Vector2{0, 0}, Vector2{10, 10}}, true, Vector2{5, 10}, Vector2{0, 1}},
+		{"box miss", LineSegment{Vector2{11, 20}, Vector2{11, -20}}, AABB{Vector2{0, 0}, Vector2{10, 10}}, false, Vector2{}, Vector2{}},
+		{"obb", LineSegment{Vector2{0, 5}, Vector2{20, 5}}, OBB{Vector2{10, 5}, Vector2{2, 2}, Angle(Pi / 2)}, true, Vector2{8, 5}, Vector2{-1, 0}},
+		{"segment", LineSegment{Vector2{0, 0}, Vector2{0, 10}}, LineSegment{Vector2{-5, 5}, Vector2{5, 5}}, true, Vector2{0, 5}, Vector2{0, -1}},
+		{"segment collinear", LineSegment{Vector2{0, 0}, Vector2{10, 0}}, LineSegment{Vector2{8, 0}, Vector2{4, 0}}, true, Vector2{4, 0}, Vector2{-1, 0}},
+		{"polygon", LineSegment{Vector2{20, 1}, Vector2{0, 1}}, ConvexPolygon{Vertices: []Vector2{{12, 0}, {16, 0}, {12, 4}}}, true, Vector2{15, 1}, Vector2{1, 1}.Normalize()},
+		{"inside", LineSegment{Vector2{5, 5}, Vector2{20, 5}}, AABB{Vector2{0, 0}, Vector2{10, 10}}, true, Vector2{5, 5}, Vector2{-1, 0}},
+	}
+
+	for _, tt := range tests {
+		hit, ok := SegmentCast(tt.seg, tt.shape)
+		if ok != tt.want {
+			t.Errorf("%s: expected hit %v, got %v", tt.name, tt.want, ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if !nearlyEqual(hit.Point, tt.point) {
+			t.Errorf("%s: expected point %v, got %v", tt.name, tt.point, hit.Point)
+		}
+		if !nearlyEqual(hit.Normal, tt.normal) {
+			t.Errorf("%s: expected normal %v, got %v", tt.name, tt.normal, hit.Normal)
+		}
+		if !nearlyEqual(tt.seg.PointOnSegment(hit.T), hit.Point) {
+			t.Errorf("%s: expected t %f to give %v", tt.name, hit.T, hit.Point)
+		}
+	}
+}
+
+func TestRayCast(t *testing.T) {
+	box := AABB{Vector2{100, -10}, Vector2{120, 10}}
+	hit, ok := RayCast(Vector2{0, 0}, Vector2{2, 0}, box)
+	if !ok || !nearlyEqual(hit.Point, Vector2{100, 0}) || hit.T != 50 {
+		t.Errorf("expected hit at (100, 0) with t 50, got %v %v", ok, hit)
+	}
+
+	if _, ok := RayCast(Vector2{0, 0}, Vector2{-1, 0}, box); ok {
+		t.Errorf("expected no hit behind the ray")
+	}
+}
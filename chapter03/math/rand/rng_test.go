@@ -0,0 +1,80 @@
+package rand
+
+import (
+	"slices"
+	"testing"
+)
+
+func draw(s *Stream, n int) []int {
+	values := make([]int, n)
+	for i := range values {
+		values[i] = s.GetInt()
+	}
+	return values
+}
+
+func TestRNGSeed(t *testing.T) {
+	a := NewRNG(1)
+	b := NewRNG(1)
+	if !slices.Equal(draw(a.GetStream(StreamSpawn), 10), draw(b.GetStream(StreamSpawn), 10)) {
+		t.Errorf("expected the same seed to give the same numbers")
+	}
+
+	c := NewRNG(2)
+	if slices.Equal(draw(NewRNG(1).GetStream(StreamSpawn), 10), draw(c.GetStream(StreamSpawn), 10)) {
+		t.Errorf("expected different seeds to give different numbers")
+	}
+
+	// Reseeding starts the streams over
+	first := draw(a.GetStream(StreamSpawn), 10)
+	a.Seed(1)
+	if got := draw(a.GetStream(StreamSpawn), 20); !slices.Equal(got[10:], first) {
+		t.Errorf("expected reseeding to restart the stream")
+	}
+}
+
+func TestRNGStreamsAreIndependent(t *testing.T) {
+	a := NewRNG(1)
+	b := NewRNG(1)
+
+	// Drawing particles from one generator shouldn't change its spawns
+	draw(a.GetStream(StreamParticles), 100)
+	if !slices.Equal(draw(a.GetStream(StreamSpawn), 10), draw(b.GetStream(StreamSpawn), 10)) {
+		t.Errorf("expected spawns to be unaffected by particles")
+	}
+
+	if slices.Equal(draw(NewRNG(1).GetStream(StreamSpawn), 10), draw(NewRNG(1).GetStream(StreamAI), 10)) {
+		t.Errorf("expected streams to have different numbers")
+	}
+}
+
+func TestRNGSaveRestore(t *testing.T) {
+	r := NewRNG(3)
+	draw(r.GetStream(StreamSpawn), 5)
+	draw(r.GetStream(StreamAI), 7)
+
+	state, err := r.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to save state: %s", err)
+	}
+	spawn := draw(r.GetStream(StreamSpawn), 10)
+	ai := draw(r.GetStream(StreamAI), 10)
+
+	restored := NewRNG(0)
+	if err := restored.UnmarshalBinary(state); err != nil {
+		t.Fatalf("failed to restore state: %s", err)
+	}
+	if restored.GetSeed() != 3 {
+		t.Errorf("expected seed 3, got %d", restored.GetSeed())
+	}
+	if !slices.Equal(draw(restored.GetStream(StreamSpawn), 10), spawn) {
+		t.Errorf("expected restored spawn stream to continue where it was saved")
+	}
+	if !slices.Equal(draw(restored.GetStream(StreamAI), 10), ai) {
+		t.Errorf("expected restored ai stream to continue where it was saved")
+	}
+
+	if err := restored.UnmarshalBinary(state[:len(state)-1]); err == nil {
+		t.Errorf("expected an error restoring truncated state")
+	}
+}
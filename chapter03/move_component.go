@@ -8,12 +8,16 @@ type MoveComponent interface {
 	SetAngularSpeed(speed float32)
 	GetForwardSpeed() float32
 	SetForwardSpeed(speed float32)
+	GetBoundsPolicy() BoundsPolicy
+	// SetBoundsPolicy sets what happens when the owner leaves the world (wraps by default)
+	SetBoundsPolicy(policy BoundsPolicy)
 }
 
 type moveComponent struct {
 	Component
 	angularSpeed float32
 	forwardSpeed float32
+	boundsPolicy BoundsPolicy
 }
 
 func NewMoveComponent(owner Actor, updateOrder int) MoveComponent {
@@ -37,20 +41,11 @@ func (m *moveComponent) Update(deltaTime float32) {
 		forward := m.GetOwner().GetForward()
 		pos = pos.Add(forward.MulScalar(m.forwardSpeed * deltaTime))
 
-		// (screen wrapping code only asteroids)
-		if pos.X < 0 {
-			pos.X = 1022
-		} else if pos.X > 1024 {
-			pos.X = 2
+		if normal := moveInBounds(m.GetOwner(), pos, m.boundsPolicy); normal != math.ZeroVector2 {
+			// Turn to head back in
+			forward = bounceOff(forward, normal)
+			m.GetOwner().SetRotation(math.Atan2(-forward.Y, forward.X))
 		}
-
-		if pos.Y < 0 {
-			pos.Y = 766
-		} else if pos.Y > 768 {
-			pos.Y = 2
-		}
-
-		m.GetOwner().SetPosition(pos)
 	}
 }
 
@@ -70,7 +65,10 @@ func (m *moveComponent) SetForwardSpeed(speed float32) {
 	m.forwardSpeed = speed
 }
 
-func (m *moveComponent) Destroy() {
-	owner := m.GetOwner()
-	owner.RemoveComponent(m)
+func (m *moveComponent) GetBoundsPolicy() BoundsPolicy {
+	return m.boundsPolicy
+}
+
+func (m *moveComponent) SetBoundsPolicy(policy BoundsPolicy) {
+	m.boundsPolicy = policy
 }
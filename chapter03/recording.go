@@ -0,0 +1,233 @@
+package chapter03
+
+import (
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+const (
+	recordingMagic   = "GGPR"
+	recordingVersion = 1
+)
+
+type recordingHeader struct {
+	Magic     [4]byte
+	Version   uint16
+	Seed      uint64
+	TickRate  float32
+	NumFrames uint32
+}
+
+// InputFrame is the input and time of one frame of the game loop.
+type InputFrame struct {
+	// Time since the previous frame (in seconds)
+	FrameTime float32
+	Input     InputState
+}
+
+// Recording holds everything needed to play a game back exactly:
+// the random seed, the tick rate, and every frame's input.
+type Recording struct {
+	Seed     uint64
+	TickRate float32
+	Frames   []InputFrame
+}
+
+func NewRecording(seed uint64, tickRate float32) *Recording {
+	return &Recording{
+		Seed:     seed,
+		TickRate: tickRate,
+	}
+}
+
+// AddFrame stores the current input of state. The previous input isn't
+// stored, since it's the input of the frame before.
+func (r *Recording) AddFrame(frameTime float32, state *InputState) {
+	frame := InputFrame{FrameTime: frameTime, Input: *state}
+	frame.Input.Keyboard.prevState = [sdl.NUM_SCANCODES]uint8{}
+	frame.Input.Mouse.prevButtons = 0
+	frame.Input.Controller.prevButtons = [sdl.CONTROLLER_BUTTON_MAX]uint8{}
+	r.Frames = append(r.Frames, frame)
+}
+
+func (r *Recording) Save(fileName string) error {
+	f, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := r.Write(f); err != nil {
+		return err
+	}
+
+	return f.Close()
+}
+
+func LoadRecording(fileName string) (*Recording, error) {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ReadRecording(f)
+}
+
+// Write encodes the recording as gzipped binary. Only the keys that are
+// down are written, so a frame is around 60 bytes before compression.
+func (r *Recording) Write(w io.Writer) error {
+	zw := gzip.NewWriter(w)
+
+	header := recordingHeader{
+		Version:   recordingVersion,
+		Seed:      r.Seed,
+		TickRate:  r.TickRate,
+		NumFrames: uint32(len(r.Frames)),
+	}
+	copy(header.Magic[:], recordingMagic)
+	if err := binary.Write(zw, binary.LittleEndian, &header); err != nil {
+		return err
+	}
+
+	for i := range r.Frames {
+		if err := writeFrame(zw, &r.Frames[i]); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+func ReadRecording(r io.Reader) (*Recording, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	var header recordingHeader
+	if err := binary.Read(zr, binary.LittleEndian, &header); err != nil {
+		return nil, err
+	}
+	if string(header.Magic[:]) != recordingMagic {
+		return nil, errors.New("not a recording")
+	}
+	if header.Version != recordingVersion {
+		return nil, fmt.Errorf("recording version %d is not supported", header.Version)
+	}
+
+	rec := NewRecording(header.Seed, header.TickRate)
+	for range header.NumFrames {
+		var frame InputFrame
+		if err := readFrame(zr, &frame); err != nil {
+			return nil, err
+		}
+		rec.Frames = append(rec.Frames, frame)
+	}
+
+	return rec, nil
+}
+
+// frameData is the fixed size part of an encoded frame
+type frameData struct {
+	FrameTime float32
+
+	MousePos     [2]float32
+	ScrollWheel  [2]float32
+	MouseButtons uint32
+	IsRelative   bool
+
+	IsConnected       bool
+	ControllerButtons uint32
+	LeftStick         [2]float32
+	RightStick        [2]float32
+	LeftTrigger       float32
+	RightTrigger      float32
+
+	NumKeys uint16
+}
+
+func writeFrame(w io.Writer, frame *InputFrame) error {
+	mouse := &frame.Input.Mouse
+	controller := &frame.Input.Controller
+
+	data := frameData{
+		FrameTime:    frame.FrameTime,
+		MousePos:     [2]float32{mouse.mousePos.X, mouse.mousePos.Y},
+		ScrollWheel:  [2]float32{mouse.scrollWheel.X, mouse.scrollWheel.Y},
+		MouseButtons: mouse.currButtons,
+		IsRelative:   mouse.isRelative,
+		IsConnected:  controller.isConnected,
+		LeftStick:    [2]float32{controller.leftStick.X, controller.leftStick.Y},
+		RightStick:   [2]float32{controller.rightStick.X, controller.rightStick.Y},
+		LeftTrigger:  controller.leftTrigger,
+		RightTrigger: controller.rightTrigger,
+	}
+	for i, b := range controller.currButtons {
+		if b == 1 {
+			data.ControllerButtons |= 1 << i
+		}
+	}
+
+	var keys []uint16
+	for key, value := range frame.Input.Keyboard.currState {
+		if value == 1 {
+			keys = append(keys, uint16(key))
+		}
+	}
+	data.NumKeys = uint16(len(keys))
+
+	if err := binary.Write(w, binary.LittleEndian, &data); err != nil {
+		return err
+	}
+
+	return binary.Write(w, binary.LittleEndian, keys)
+}
+
+func readFrame(r io.Reader, frame *InputFrame) error {
+	var data frameData
+	if err := binary.Read(r, binary.LittleEndian, &data); err != nil {
+		return err
+	}
+
+	keys := make([]uint16, data.NumKeys)
+	if err := binary.Read(r, binary.LittleEndian, keys); err != nil {
+		return err
+	}
+
+	frame.FrameTime = data.FrameTime
+
+	mouse := &frame.Input.Mouse
+	mouse.mousePos.X, mouse.mousePos.Y = data.MousePos[0], data.MousePos[1]
+	mouse.scrollWheel.X, mouse.scrollWheel.Y = data.ScrollWheel[0], data.ScrollWheel[1]
+	mouse.currButtons = data.MouseButtons
+	mouse.isRelative = data.IsRelative
+
+	controller := &frame.Input.Controller
+	controller.isConnected = data.IsConnected
+	for i := range controller.currButtons {
+		if data.ControllerButtons&(1<<i) != 0 {
+			controller.currButtons[i] = 1
+		}
+	}
+	controller.leftStick.X, controller.leftStick.Y = data.LeftStick[0], data.LeftStick[1]
+	controller.rightStick.X, controller.rightStick.Y = data.RightStick[0], data.RightStick[1]
+	controller.leftTrigger = data.LeftTrigger
+	controller.rightTrigger = data.RightTrigger
+
+	for _, key := range keys {
+		if int(key) >= sdl.NUM_SCANCODES {
+			return fmt.Errorf("invalid key %d in recording", key)
+		}
+		frame.Input.Keyboard.currState[key] = 1
+	}
+
+	return nil
+}
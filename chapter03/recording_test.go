@@ -0,0 +1,147 @@
+package chapter03
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// newTestRecording records a few seconds of a player thrusting,
+// turning and firing
+func newTestRecording(seed uint64) *Recording {
+	rec := NewRecording(seed, DefaultTickRate)
+
+	var state InputState
+	for i := range 300 {
+		state.Keyboard.currState = [sdl.NUM_SCANCODES]uint8{}
+		state.Keyboard.currState[sdl.SCANCODE_W] = 1
+		if i%50 < 20 {
+			state.Keyboard.currState[sdl.SCANCODE_A] = 1
+		}
+		if i%30 == 0 {
+			state.Keyboard.currState[sdl.SCANCODE_SPACE] = 1
+		}
+		state.Controller.leftStick.X = float32(i%7) / 7
+
+		// Uneven frame times, like a real game
+		rec.AddFrame(float32(10+i%13)/1000.0, &state)
+	}
+
+	return rec
+}
+
+func TestRecordingReadWrite(t *testing.T) {
+	rec := newTestRecording(42)
+
+	var buf bytes.Buffer
+	if err := rec.Write(&buf); err != nil {
+		t.Fatalf("failed to write recording: %s", err)
+	}
+
+	loaded, err := ReadRecording(&buf)
+	if err != nil {
+		t.Fatalf("failed to read recording: %s", err)
+	}
+
+	if loaded.Seed != rec.Seed || loaded.TickRate != rec.TickRate {
+		t.Errorf("expected seed %d and tick rate %f, got %d and %f", rec.Seed, rec.TickRate, loaded.Seed, loaded.TickRate)
+	}
+	if len(loaded.Frames) != len(rec.Frames) {
+		t.Fatalf("expected %d frames, got %d", len(rec.Frames), len(loaded.Frames))
+	}
+	for i := range rec.Frames {
+		if loaded.Frames[i] != rec.Frames[i] {
+			t.Fatalf("frame %d differs after reading", i)
+		}
+	}
+}
+
+func TestReadRecordingRejectsOtherData(t *testing.T) {
+	if _, err := ReadRecording(bytes.NewReader([]byte("not a recording"))); err == nil {
+		t.Errorf("expected an error reading invalid data")
+	}
+}
+
+func replayGame(t *testing.T, rec *Recording) *Game {
+	t.Helper()
+
+	g := NewHeadlessGame(NewManualClock())
+	g.StartReplay(rec)
+	if err := g.Initialize(); err != nil {
+		t.Fatalf("failed to initialize headless game: %s", err)
+	}
+	t.Cleanup(func() {
+		_ = g.Shutdown()
+	})
+
+	for g.IsRunning() {
+		g.RunFrame()
+	}
+
+	return g
+}
+
+func TestReplayIsDeterministic(t *testing.T) {
+	rec := newTestRecording(42)
+
+	a := replayGame(t, rec)
+	b := replayGame(t, rec)
+
+	if a.IsReplaying() {
+		t.Errorf("expected replay to finish")
+	}
+
+	// The ship should have moved from its start
+	if pos := a.GetShip().GetPosition(); pos.X == 512 && pos.Y == 384 {
+		t.Errorf("expected the recorded input to move the ship")
+	}
+
+	if a.GetShip().GetPosition() != b.GetShip().GetPosition() {
+		t.Errorf("ship positions differ: %v and %v", a.GetShip().GetPosition(), b.GetShip().GetPosition())
+	}
+	if a.GetShip().GetRotation() != b.GetShip().GetRotation() {
+		t.Errorf("ship rotations differ: %v and %v", a.GetShip().GetRotation(), b.GetShip().GetRotation())
+	}
+
+	astA, astB := a.GetAsteroids(), b.GetAsteroids()
+	if len(astA) != len(astB) {
+		t.Fatalf("expected the same number of asteroids, got %d and %d", len(astA), len(astB))
+	}
+	for i := range astA {
+		if astA[i].GetPosition() != astB[i].GetPosition() {
+			t.Errorf("asteroid %d differs: %v and %v", i, astA[i].GetPosition(), astB[i].GetPosition())
+		}
+	}
+}
+
+func TestRecordThenReplay(t *testing.T) {
+	clock := NewManualClock()
+	g := NewHeadlessGame(clock)
+	g.StartRecording(7)
+	if err := g.Initialize(); err != nil {
+		t.Fatalf("failed to initialize headless game: %s", err)
+	}
+	t.Cleanup(func() {
+		_ = g.Shutdown()
+	})
+
+	for i := range 120 {
+		clock.Advance(time.Duration(10+i%9) * time.Millisecond)
+		g.RunFrame()
+	}
+	rec := g.StopRecording()
+
+	if len(rec.Frames) != 120 {
+		t.Fatalf("expected 120 recorded frames, got %d", len(rec.Frames))
+	}
+
+	replayed := replayGame(t, rec)
+	astA, astB := g.GetAsteroids(), replayed.GetAsteroids()
+	for i := range astA {
+		if astA[i].GetPosition() != astB[i].GetPosition() {
+			t.Errorf("asteroid %d differs: %v and %v", i, astA[i].GetPosition(), astB[i].GetPosition())
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package chapter03
+
+import "github.com/ishtaka/go-game-programming/chapter03/math"
+
+// RigidBodyComponent moves its owner by velocity, which changes with the forces
+// and impulses applied to it.
+type RigidBodyComponent interface {
+	Component
+	GetMass() float32
+	// SetMass sets the mass, where 0 is infinite mass (forces and impulses don't move it)
+	SetMass(mass float32)
+	GetInverseMass() float32
+	GetVelocity() math.Vector2
+	SetVelocity(velocity math.Vector2)
+	// GetAcceleration returns the acceleration from the forces in the last update
+	GetAcceleration() math.Vector2
+	GetAngularVelocity() float32
+	SetAngularVelocity(speed float32)
+	GetDrag() float32
+	// SetDrag sets how strongly the body is slowed, as a force against the velocity
+	SetDrag(drag float32)
+	GetRestitution() float32
+	// SetRestitution sets how bouncy the body is, from 0 (stops dead) to 1 (keeps all its speed)
+	SetRestitution(restitution float32)
+	// AddForce pushes the body during the next update
+	AddForce(force math.Vector2)
+	// ApplyImpulse changes the velocity immediately
+	ApplyImpulse(impulse math.Vector2)
+	GetBoundsPolicy() BoundsPolicy
+	// SetBoundsPolicy sets what happens when the owner leaves the world (wraps by default)
+	SetBoundsPolicy(policy BoundsPolicy)
+}
+
+type rigidBodyComponent struct {
+	Component
+	mass            float32
+	velocity        math.Vector2
+	acceleration    math.Vector2
+	angularVelocity float32
+	drag            float32
+	restitution     float32
+	// Forces added since the last update
+	sumOfForces  math.Vector2
+	boundsPolicy BoundsPolicy
+}
+
+func NewRigidBodyComponent(owner Actor, updateOrder int) RigidBodyComponent {
+	c := NewComponent(owner, updateOrder)
+	rb := &rigidBodyComponent{
+		Component:   c,
+		mass:        1,
+		restitution: 1,
+	}
+
+	return rb
+}
+
+func (r *rigidBodyComponent) Update(deltaTime float32) {
+	owner := r.GetOwner()
+
+	if !math.NearZero(r.angularVelocity) {
+		rot := owner.GetRotation()
+		rot = rot.Add(r.angularVelocity * deltaTime)
+		owner.SetRotation(rot)
+	}
+
+	// Semi-implicit Euler: the new velocity is used to move,
+	// which is as cheap as explicit Euler but much more stable
+	force := r.sumOfForces.Sub(r.velocity.MulScalar(r.drag))
+	r.acceleration = force.MulScalar(r.GetInverseMass())
+	r.velocity = r.velocity.Add(r.acceleration.MulScalar(deltaTime))
+	r.sumOfForces = math.ZeroVector2
+
+	if r.velocity != math.ZeroVector2 {
+		pos := owner.GetPosition().Add(r.velocity.MulScalar(deltaTime))
+
+		if normal := moveInBounds(owner, pos, r.boundsPolicy); normal != math.ZeroVector2 {
+			// Bounce off the edge, losing speed like hitting another body
+			bounced := bounceOff(r.velocity, normal)
+			r.velocity = r.velocity.Add(bounced.Sub(r.velocity).MulScalar((1 + r.restitution) / 2))
+		}
+	}
+}
+
+func (r *rigidBodyComponent) GetMass() float32 {
+	return r.mass
+}
+
+func (r *rigidBodyComponent) SetMass(mass float32) {
+	r.mass = mass
+}
+
+func (r *rigidBodyComponent) GetInverseMass() float32 {
+	if r.mass <= 0 {
+		return 0
+	}
+
+	return 1 / r.mass
+}
+
+func (r *rigidBodyComponent) GetVelocity() math.Vector2 {
+	return r.velocity
+}
+
+func (r *rigidBodyComponent) SetVelocity(velocity math.Vector2) {
+	r.velocity = velocity
+}
+
+func (r *rigidBodyComponent) GetAcceleration() math.Vector2 {
+	return r.acceleration
+}
+
+func (r *rigidBodyComponent) GetAngularVelocity() float32 {
+	return r.angularVelocity
+}
+
+func (r *rigidBodyComponent) SetAngularVelocity(speed float32) {
+	r.angularVelocity = speed
+}
+
+func (r *rigidBodyComponent) GetDrag() float32 {
+	return r.drag
+}
+
+func (r *rigidBodyComponent) SetDrag(drag float32) {
+	r.drag = drag
+}
+
+func (r *rigidBodyComponent) GetRestitution() float32 {
+	return r.restitution
+}
+
+func (r *rigidBodyComponent) SetRestitution(restitution float32) {
+	r.restitution = restitution
+}
+
+func (r *rigidBodyComponent) AddForce(force math.Vector2) {
+	r.sumOfForces = r.sumOfForces.Add(force)
+}
+
+func (r *rigidBodyComponent) ApplyImpulse(impulse math.Vector2) {
+	r.velocity = r.velocity.Add(impulse.MulScalar(r.GetInverseMass()))
+}
+
+func (r *rigidBodyComponent) GetBoundsPolicy() BoundsPolicy {
+	return r.boundsPolicy
+}
+
+func (r *rigidBodyComponent) SetBoundsPolicy(policy BoundsPolicy) {
+	r.boundsPolicy = policy
+}
+
+// Bounce pushes apart the owners of two touching circles, and applies equal and
+// opposite impulses to their bodies using the lower of their restitutions.
+// Bodies already moving apart aren't changed, so both owners can call it for the same contact.
+func Bounce(a, b RigidBodyComponent, circleA, circleB CircleComponent) {
+	invMassA, invMassB := a.GetInverseMass(), b.GetInverseMass()
+	invMassSum := invMassA + invMassB
+	if invMassSum == 0 {
+		return
+	}
+
+	// Normal from a to b (any direction will do if they're on top of each other)
+	delta := circleB.GetCenter().Sub(circleA.GetCenter())
+	dist := delta.Length()
+	normal := math.Vector2{X: 1, Y: 0}
+	if dist > 0 {
+		normal = delta.MulScalar(1 / dist)
+	}
+
+	// Move them apart, the lighter one further
+	if overlap := circleA.GetRadius() + circleB.GetRadius() - dist; overlap > 0 {
+		correction := normal.MulScalar(overlap / invMassSum)
+		ownerA, ownerB := a.GetOwner(), b.GetOwner()
+		ownerA.SetPosition(ownerA.GetPosition().Sub(correction.MulScalar(invMassA)))
+		ownerB.SetPosition(ownerB.GetPosition().Add(correction.MulScalar(invMassB)))
+	}
+
+	closingSpeed := b.GetVelocity().Sub(a.GetVelocity()).Dot(normal)
+	if closingSpeed > 0 {
+		return
+	}
+
+	restitution := math.Min(a.GetRestitution(), b.GetRestitution())
+	impulse := normal.MulScalar(-(1 + restitution) * closingSpeed / invMassSum)
+	a.ApplyImpulse(impulse.MulScalar(-1))
+	b.ApplyImpulse(impulse)
+}
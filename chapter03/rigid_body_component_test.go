@@ -0,0 +1,131 @@
+package chapter03
+
+import (
+	"testing"
+
+	"github.com/ishtaka/go-game-programming/chapter03/math"
+)
+
+func newBodyActor(g *Game, pos math.Vector2) (Actor, RigidBodyComponent) {
+	a := NewActor(g)
+	a.SetPosition(pos)
+	rb := NewRigidBodyComponent(a, DefaultUpdateOrder)
+	a.AddComponent(rb)
+	g.AddActor(a)
+
+	return a, rb
+}
+
+func TestRigidBodyForces(t *testing.T) {
+	g := newHeadlessGame(t)
+	a, rb := newBodyActor(g, math.Vector2{X: 100, Y: 100})
+	rb.SetMass(2)
+
+	// A force for one update gives its acceleration for that update,
+	// and the body moves by the new velocity (semi-implicit Euler)
+	rb.AddForce(math.Vector2{X: 120})
+	g.Step(0.5)
+	if acc := rb.GetAcceleration(); acc.X != 60 {
+		t.Errorf("expected acceleration 60, got %v", acc)
+	}
+	if vel := rb.GetVelocity(); vel.X != 30 {
+		t.Errorf("expected velocity 30, got %v", vel)
+	}
+	if pos := a.GetPosition(); pos.X != 115 {
+		t.Errorf("expected x 115, got %v", pos)
+	}
+
+	// The force is used up, so the body keeps going at the same speed
+	g.Step(0.5)
+	if vel := rb.GetVelocity(); vel.X != 30 {
+		t.Errorf("expected velocity 30 without forces, got %v", vel)
+	}
+
+	rb.ApplyImpulse(math.Vector2{Y: 10})
+	if vel := rb.GetVelocity(); vel.Y != 5 {
+		t.Errorf("expected impulse to add 5 to velocity, got %v", vel)
+	}
+}
+
+func TestRigidBodyDrag(t *testing.T) {
+	g := newHeadlessGame(t)
+	_, rb := newBodyActor(g, math.Vector2{X: 100, Y: 100})
+	rb.SetDrag(2)
+
+	// With a steady force, drag brings the body to force / drag
+	for range 600 {
+		rb.AddForce(math.Vector2{X: 100})
+		g.Step(1.0 / 60.0)
+	}
+	if vel := rb.GetVelocity(); math.Abs(vel.X-50) > 0.01 {
+		t.Errorf("expected terminal velocity 50, got %v", vel)
+	}
+
+	// And then slows it to a stop
+	for range 600 {
+		g.Step(1.0 / 60.0)
+	}
+	if vel := rb.GetVelocity(); !math.NearZero(vel.X) {
+		t.Errorf("expected the body to stop, got %v", vel)
+	}
+}
+
+func TestAsteroidsBounce(t *testing.T) {
+	g := newHeadlessGame(t)
+	for _, ast := range g.GetAsteroids() {
+		ast.SetState(Dead)
+	}
+	g.Step(1.0 / 60.0)
+
+	a := NewAsteroid(g, DefaultDrawOrder)
+	a.SetPosition(math.Vector2{X: 400, Y: 300})
+	aBody, _ := GetComponent[RigidBodyComponent](a)
+	aBody.SetVelocity(math.Vector2{X: 100})
+	aBody.SetRestitution(1)
+	b := NewAsteroid(g, DefaultDrawOrder)
+	b.SetPosition(math.Vector2{X: 600, Y: 300})
+	bBody, _ := GetComponent[RigidBodyComponent](b)
+	bBody.SetVelocity(math.Vector2{X: -100})
+	bBody.SetRestitution(1)
+
+	for range 120 {
+		g.Step(1.0 / 60.0)
+	}
+
+	// Equal masses swap velocities in an elastic collision
+	if vel := aBody.GetVelocity(); !math.NearZero(vel.X+100) || !math.NearZero(vel.Y) {
+		t.Errorf("expected the first asteroid to bounce back, got %v", vel)
+	}
+	if vel := bBody.GetVelocity(); !math.NearZero(vel.X-100) || !math.NearZero(vel.Y) {
+		t.Errorf("expected the second asteroid to bounce back, got %v", vel)
+	}
+	if a.GetPosition().X >= b.GetPosition().X {
+		t.Errorf("expected the asteroids not to pass through each other")
+	}
+}
+
+func TestShipCoasts(t *testing.T) {
+	g := newHeadlessGame(t)
+	ship := g.GetShip()
+	body, _ := GetComponent[RigidBodyComponent](ship)
+
+	ship.thrust = shipThrust
+	for range 30 {
+		g.Step(1.0 / 60.0)
+	}
+	ship.thrust = 0
+	g.Step(1.0 / 60.0)
+
+	// Still moving after letting go of thrust
+	speed := body.GetVelocity().Length()
+	if speed <= 0 || speed > 300 {
+		t.Fatalf("expected the ship to be moving below top speed, got %f", speed)
+	}
+
+	for range 30 {
+		g.Step(1.0 / 60.0)
+	}
+	if slower := body.GetVelocity().Length(); slower >= speed || slower <= 0 {
+		t.Errorf("expected the ship to slow down gradually from %f, got %f", speed, slower)
+	}
+}
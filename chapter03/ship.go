@@ -2,12 +2,19 @@ package chapter03
 
 import (
 	"github.com/ishtaka/go-game-programming/chapter03/math"
-	"github.com/veandco/go-sdl2/sdl"
+)
+
+// Thrust and drag on the ship, which make its top speed 300
+const (
+	shipThrust float32 = 450
+	shipDrag   float32 = 1.5
 )
 
 type Ship struct {
 	Actor
 	laserCoolDown float32
+	// Force forward from the thrust input
+	thrust float32
 }
 
 func NewShip(game *Game, drawOrder int) *Ship {
@@ -18,19 +25,28 @@ func NewShip(game *Game, drawOrder int) *Ship {
 	// create a sprite component
 	sc := NewSpriteComponent(s, drawOrder)
 	sc.SetTexture(game.GetTexture("Assets/Ship.png"))
-	game.AddSprite(sc)
 	s.AddComponent(sc)
 
-	// create an input component and set keys/speed
+	// create an input component for turning (thrust pushes the rigid body)
 	ic := NewInputComponent(s, DefaultUpdateOrder)
-	ic.SetForwardKey(sdl.SCANCODE_W)
-	ic.SetBackKey(sdl.SCANCODE_S)
-	ic.SetClockwiseKey(sdl.SCANCODE_A)
-	ic.SetCounterClockwiseKey(sdl.SCANCODE_D)
-	ic.SetMaxForwardSpeed(300)
+	ic.SetAngularAxis("Turn")
 	ic.SetMaxAngularSpeed(math.TwoPi)
 	s.AddComponent(ic)
 
+	// create a rigid body, which drifts to a stop without thrust
+	rb := NewRigidBodyComponent(s, DefaultUpdateOrder)
+	rb.SetDrag(shipDrag)
+	rb.SetBoundsPolicy(BoundsClamp)
+	s.AddComponent(rb)
+
+	// create a circle component on the ship's own layer, so its lasers
+	// and asteroids pass through it
+	cc := NewCircleComponent(s, DefaultUpdateOrder)
+	cc.SetRadius(32)
+	cc.SetLayer(LayerShip)
+	cc.SetMask(LayerNone)
+	s.AddComponent(cc)
+
 	game.AddActor(s)
 
 	return s
@@ -38,6 +54,11 @@ func NewShip(game *Game, drawOrder int) *Ship {
 
 func (s *Ship) Update(deltaTime float32) {
 	if s.GetState() == Active {
+		// Forces only last one update, so keep thrusting until the input changes
+		if rb, ok := GetComponent[RigidBodyComponent](s); ok {
+			rb.AddForce(s.GetForward().MulScalar(s.thrust))
+		}
+
 		s.Actor.Update(deltaTime)
 		s.UpdateActor(deltaTime)
 	}
@@ -47,15 +68,17 @@ func (s *Ship) UpdateActor(deltaTime float32) {
 	s.laserCoolDown -= deltaTime
 }
 
-func (s *Ship) ProcessInput(keyState []uint8) {
-	s.Actor.ProcessInput(keyState)
+func (s *Ship) ProcessInput(state *InputState) {
+	s.Actor.ProcessInput(state)
 	if s.GetState() == Active {
-		s.ActorInput(keyState)
+		s.ActorInput(state)
 	}
 }
 
-func (s *Ship) ActorInput(keyState []uint8) {
-	if keyState[sdl.SCANCODE_SPACE] != 0 && s.laserCoolDown <= 0.0 {
+func (s *Ship) ActorInput(state *InputState) {
+	s.thrust = shipThrust * s.GetGame().GetInputMap().GetAxisValue(state, "Thrust")
+
+	if s.GetGame().GetInputMap().GetActionValue(state, "Fire") && s.laserCoolDown <= 0.0 {
 		// Create a laser and set its position/rotation to mine
 		laser := NewLaser(s.GetGame(), DefaultUpdateOrder)
 		laser.SetPosition(s.GetPosition())
@@ -65,8 +88,3 @@ func (s *Ship) ActorInput(keyState []uint8) {
 		s.laserCoolDown = 0.5
 	}
 }
-
-func (s *Ship) Destroy() {
-	s.GetGame().RemoveActor(s)
-	s.DestroyComponents()
-}
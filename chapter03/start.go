@@ -3,7 +3,7 @@ package chapter03
 import "log"
 
 func Start() {
-	game := NewGame()
+	game := NewGame(NewSDLClock())
 	defer func() {
 		err := game.Shutdown()
 		if err != nil {
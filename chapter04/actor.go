@@ -31,9 +31,9 @@ type Actor interface {
 	UpdateActor(deltaTime float32)
 
 	// ProcessInput function called from Game (not overridable)
-	ProcessInput(keyState []uint8)
+	ProcessInput(state *InputState)
 	// ActorInput any actor-specific input code (overridable)
-	ActorInput(keyState []uint8)
+	ActorInput(state *InputState)
 
 	GetPosition() math.Vector2
 	SetPosition(v math.Vector2)
@@ -43,16 +43,38 @@ type Actor interface {
 	SetRotation(r math.Angle)
 	GetForward() math.Vector2
 
+	// SavePreviousTransform keeps the current transform to interpolate from (called from Game)
+	SavePreviousTransform()
+	// GetInterpolatedPosition returns the position between the previous and current transform
+	GetInterpolatedPosition(alpha float32) math.Vector2
+	// GetInterpolatedRotation returns the rotation between the previous and current transform
+	GetInterpolatedRotation(alpha float32) math.Angle
+
 	GetState() State
 	SetState(s State)
 
 	GetGame() *Game
 
+	// AddComponent adds the component and registers it with the game
 	AddComponent(c Component)
+	// RemoveComponent removes the component and unregisters it from the game
 	RemoveComponent(c Component)
-
-	Destroy()           // must override if embedded in a struct
-	DestroyComponents() // must be called in Destroy if embedded in a struct
+	// GetComponents returns the components in update order
+	GetComponents() []Component
+
+	// OnAdded called after the actor is added to the game (overridable)
+	OnAdded()
+	// OnRemoved called after the actor is removed from the game (overridable)
+	OnRemoved()
+	// OnDestroy called when the actor is destroyed, before its components are removed (overridable)
+	OnDestroy()
+
+	// OnCollisionEnter called when one of the actor's circles starts touching other (overridable)
+	OnCollisionEnter(other CircleComponent)
+	// OnCollisionStay called each update the circles are still touching (overridable)
+	OnCollisionStay(other CircleComponent)
+	// OnCollisionExit called when the circles stop touching (overridable)
+	OnCollisionExit(other CircleComponent)
 }
 
 type actor struct {
@@ -62,6 +84,9 @@ type actor struct {
 	rotation   math.Angle
 	components []Component
 	game       *Game
+	// Transform before the last update, for interpolation
+	prevPosition math.Vector2
+	prevRotation math.Angle
 }
 
 func NewActor(game *Game) Actor {
@@ -94,18 +119,18 @@ func (a *actor) UpdateComponents(deltaTime float32) {
 
 func (a *actor) UpdateActor(deltaTime float32) {}
 
-func (a *actor) ProcessInput(keyState []uint8) {
+func (a *actor) ProcessInput(state *InputState) {
 	if a.state == Active {
 		// first process input for components
 		for _, c := range a.components {
-			c.ProcessInput(keyState)
+			c.ProcessInput(state)
 		}
 
-		a.ActorInput(keyState)
+		a.ActorInput(state)
 	}
 }
 
-func (a *actor) ActorInput(keyState []uint8) {}
+func (a *actor) ActorInput(state *InputState) {}
 
 func (a *actor) GetPosition() math.Vector2 {
 	return a.position
@@ -138,12 +163,37 @@ func (a *actor) GetForward() math.Vector2 {
 	}
 }
 
+func (a *actor) SavePreviousTransform() {
+	a.prevPosition = a.position
+	a.prevRotation = a.rotation
+}
+
+func (a *actor) GetInterpolatedPosition(alpha float32) math.Vector2 {
+	return a.prevPosition.Lerp(a.position, alpha)
+}
+
+func (a *actor) GetInterpolatedRotation(alpha float32) math.Angle {
+	return a.prevRotation.Lerp(a.rotation, alpha)
+}
+
 func (a *actor) GetState() State {
 	return a.state
 }
 
 func (a *actor) SetState(s State) {
+	wasActive := a.state == Active
 	a.state = s
+
+	// Let components know when the actor starts or stops being active
+	if isActive := s == Active; isActive != wasActive {
+		for _, c := range a.components {
+			if isActive {
+				c.OnEnable()
+			} else {
+				c.OnDisable()
+			}
+		}
+	}
 }
 
 func (a *actor) GetGame() *Game {
@@ -162,21 +212,66 @@ func (a *actor) AddComponent(c Component) {
 
 	// Insert at position
 	a.components = slices.Insert(a.components, insertIndex, c)
+
+	a.game.addComponent(c)
+	c.OnAdded()
 }
 
 func (a *actor) RemoveComponent(c Component) {
+	if !slices.Contains(a.components, c) {
+		return
+	}
+
 	a.components = slices.DeleteFunc(a.components, func(c2 Component) bool {
 		return c == c2
 	})
+
+	a.game.removeComponent(c)
+	c.OnRemoved()
 }
 
-func (a *actor) Destroy() {
-	a.game.RemoveActor(a)
-	a.DestroyComponents()
+func (a *actor) GetComponents() []Component {
+	return a.components
 }
 
-func (a *actor) DestroyComponents() {
-	for len(a.components) > 0 {
-		a.components[0].Destroy()
+// GetComponent returns the first component of the actor that is a T.
+func GetComponent[T Component](a Actor) (T, bool) {
+	for _, c := range a.GetComponents() {
+		if t, ok := c.(T); ok {
+			return t, true
+		}
+	}
+
+	var zero T
+	return zero, false
+}
+
+// GetComponents returns every component of the actor that is a T.
+func GetComponents[T Component](a Actor) []T {
+	var found []T
+	for _, c := range a.GetComponents() {
+		if t, ok := c.(T); ok {
+			found = append(found, t)
+		}
 	}
+
+	return found
 }
+
+// HasComponent reports whether the actor has a component that is a T.
+func HasComponent[T Component](a Actor) bool {
+	_, ok := GetComponent[T](a)
+	return ok
+}
+
+func (a *actor) OnAdded() {}
+
+func (a *actor) OnRemoved() {}
+
+func (a *actor) OnDestroy() {}
+
+func (a *actor) OnCollisionEnter(other CircleComponent) {}
+
+func (a *actor) OnCollisionStay(other CircleComponent) {}
+
+func (a *actor) OnCollisionExit(other CircleComponent) {}
@@ -2,7 +2,6 @@ package chapter04
 
 type Bullet struct {
 	Actor
-	circle   CircleComponent
 	liveTime float32
 }
 
@@ -14,7 +13,6 @@ func NewBullet(game *Game, drawOrder int) *Bullet {
 	// create a sprite component
 	sc := NewSpriteComponent(l, drawOrder)
 	sc.SetTexture(game.GetTexture("Assets/Projectile.png"))
-	game.AddSprite(sc)
 	l.AddComponent(sc)
 
 	// create a move component, and set a forward speed
@@ -23,9 +21,11 @@ func NewBullet(game *Game, drawOrder int) *Bullet {
 	l.AddComponent(mc)
 
 	// create a circle component
-	l.circle = NewCircleComponent(l, DefaultUpdateOrder)
-	l.circle.SetRadius(5)
-	l.AddComponent(l.circle)
+	cc := NewCircleComponent(l, DefaultUpdateOrder)
+	cc.SetRadius(5)
+	cc.SetLayer(LayerBullet)
+	cc.SetMask(LayerEnemy)
+	l.AddComponent(cc)
 
 	l.liveTime = 1.0
 
@@ -45,16 +45,6 @@ func (l *Bullet) Update(deltaTime float32) {
 func (l *Bullet) UpdateActor(deltaTime float32) {
 	l.Actor.UpdateActor(deltaTime)
 
-	// Check for collision vs enemies
-	for _, e := range l.GetGame().GetEnemies() {
-		if Intersect(l.circle, e.GetCircle()) {
-			// We both die on collision
-			e.SetState(Dead)
-			l.SetState(Dead)
-			break
-		}
-	}
-
 	l.liveTime -= deltaTime
 	if l.liveTime <= 0.0 {
 		// Time limit hit, die
@@ -62,7 +52,11 @@ func (l *Bullet) UpdateActor(deltaTime float32) {
 	}
 }
 
-func (l *Bullet) Destroy() {
-	l.GetGame().RemoveActor(l)
-	l.DestroyComponents()
+// OnCollisionEnter destroys the bullet and the enemy it hits
+func (l *Bullet) OnCollisionEnter(other CircleComponent) {
+	if e, ok := other.GetOwner().(*Enemy); ok {
+		// We both die on collision
+		e.SetState(Dead)
+		l.SetState(Dead)
+	}
 }
@@ -0,0 +1,76 @@
+package chapter04
+
+import (
+	"time"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// Clock is the source of time for the game loop.
+type Clock interface {
+	// Now returns the time elapsed since the clock started
+	Now() time.Duration
+}
+
+// SDLClock reads the time from SDL's millisecond tick counter.
+type SDLClock struct{}
+
+func NewSDLClock() *SDLClock {
+	return &SDLClock{}
+}
+
+func (c *SDLClock) Now() time.Duration {
+	return time.Duration(sdl.GetTicks64()) * time.Millisecond
+}
+
+// ManualClock only moves forward when advanced.
+type ManualClock struct {
+	now time.Duration
+}
+
+func NewManualClock() *ManualClock {
+	return &ManualClock{}
+}
+
+func (c *ManualClock) Now() time.Duration {
+	return c.now
+}
+
+// Advance moves the clock forward by d.
+func (c *ManualClock) Advance(d time.Duration) {
+	c.now += d
+}
+
+// ScaledClock runs another clock faster or slower.
+// A scale of 0 pauses it.
+type ScaledClock struct {
+	source Clock
+	scale  float32
+	// Source and scaled time when the scale last changed
+	sourceStart time.Duration
+	start       time.Duration
+}
+
+func NewScaledClock(source Clock) *ScaledClock {
+	return &ScaledClock{
+		source:      source,
+		scale:       1.0,
+		sourceStart: source.Now(),
+	}
+}
+
+func (c *ScaledClock) Now() time.Duration {
+	elapsed := c.source.Now() - c.sourceStart
+	return c.start + time.Duration(float64(elapsed)*float64(c.scale))
+}
+
+func (c *ScaledClock) GetScale() float32 {
+	return c.scale
+}
+
+func (c *ScaledClock) SetScale(scale float32) {
+	// Time already passed keeps the previous scale
+	c.start = c.Now()
+	c.sourceStart = c.source.Now()
+	c.scale = scale
+}
@@ -2,7 +2,6 @@ package chapter04
 
 type Enemy struct {
 	Actor
-	circle CircleComponent
 }
 
 func NewEnemy(game *Game, drawOrder int) *Enemy {
@@ -12,28 +11,36 @@ func NewEnemy(game *Game, drawOrder int) *Enemy {
 
 	sc := NewSpriteComponent(e, drawOrder)
 	sc.SetTexture(game.GetTexture("Assets/Airplane.png"))
-	game.AddSprite(sc)
 	e.AddComponent(sc)
 
-	// Set position at start tile
-	e.SetPosition(game.GetGrid().GetStartTile().GetPosition())
-
 	nc := NewNavComponent(e, DefaultUpdateOrder)
 	nc.SetForwardSpeed(150)
-	nc.StartPath(game.GetGrid().GetStartTile())
+	nc.SetFlowField(game.GetGrid().GetFlowField())
 	e.AddComponent(nc)
 
+	// Set position at start tile
+	e.StartAt(game.GetGrid().GetStartTile())
+
 	// Set up the circle for collision
-	e.circle = NewCircleComponent(e, DefaultUpdateOrder)
-	e.circle.SetRadius(25.0)
-	e.AddComponent(e.circle)
+	cc := NewCircleComponent(e, DefaultUpdateOrder)
+	cc.SetRadius(25.0)
+	cc.SetLayer(LayerEnemy)
+	cc.SetMask(LayerBullet)
+	e.AddComponent(cc)
 
-	game.AddEnemy(e)
 	game.AddActor(e)
 
 	return e
 }
 
+// StartAt puts the enemy on a start tile, heading for the base.
+func (s *Enemy) StartAt(t *Tile) {
+	s.SetPosition(t.GetPosition())
+	if nc, ok := GetComponent[NavComponent](s); ok {
+		nc.StartPath(t)
+	}
+}
+
 func (s *Enemy) Update(deltaTime float32) {
 	if s.GetState() == Active {
 		s.Actor.Update(deltaTime)
@@ -44,19 +51,20 @@ func (s *Enemy) Update(deltaTime float32) {
 func (s *Enemy) UpdateActor(deltaTime float32) {
 	s.Actor.UpdateActor(deltaTime)
 
-	// Am I near the end tile?
-	diff := s.GetPosition().Sub(s.GetGame().GetGrid().GetEndTile().GetPosition())
-	if diff.Length() <= 10 {
-		s.SetState(Dead)
+	// Am I near an end tile?
+	for _, t := range s.GetGame().GetGrid().GetEndTiles() {
+		diff := s.GetPosition().Sub(t.GetPosition())
+		if diff.Length() <= 10 {
+			s.SetState(Dead)
+			break
+		}
 	}
 }
 
-func (s *Enemy) GetCircle() CircleComponent {
-	return s.circle
+func (s *Enemy) OnAdded() {
+	s.GetGame().AddEnemy(s)
 }
 
-func (s *Enemy) Destroy() {
-	s.GetGame().RemoveActor(s)
+func (s *Enemy) OnRemoved() {
 	s.GetGame().RemoveEnemy(s)
-	s.DestroyComponents()
 }
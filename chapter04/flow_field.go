@@ -0,0 +1,190 @@
+package chapter04
+
+import (
+	"slices"
+
+	"github.com/ishtaka/go-game-programming/chapter04/math"
+	"github.com/ishtaka/go-game-programming/chapter04/search"
+)
+
+// FlowField points every tile on a grid toward the nearest of its goal
+// tiles, so any number of enemies can find their way from wherever they are.
+type FlowField struct {
+	grid  *Grid
+	goals []*Tile
+	// Integration field: cost from each tile to the nearest goal
+	cost map[*Tile]float32
+	// The tile each tile's cost came from, so Block knows what depends on what
+	parent map[*Tile]*Tile
+}
+
+func NewFlowField(grid *Grid, goals ...*Tile) *FlowField {
+	f := &FlowField{grid: grid}
+	f.SetGoals(goals...)
+
+	return f
+}
+
+// SetGoals changes the goal tiles, and recomputes the whole field.
+func (f *FlowField) SetGoals(goals ...*Tile) {
+	f.goals = goals
+	f.Rebuild()
+}
+
+func (f *FlowField) GetGoals() []*Tile {
+	return f.goals
+}
+
+// Rebuild recomputes the whole field, as after many tiles change.
+func (f *FlowField) Rebuild() {
+	var goals []*Tile
+	for _, goal := range f.goals {
+		if !goal.blocked {
+			goals = append(goals, goal)
+		}
+	}
+	d := search.DistancesTo[*Tile](f.grid, goals...)
+
+	f.cost = make(map[*Tile]float32)
+	f.parent = make(map[*Tile]*Tile)
+	for _, row := range f.grid.tiles {
+		for _, t := range row {
+			if cost, ok := d.GetDistance(t); ok {
+				f.cost[t] = cost
+			}
+			if p, ok := d.GetParent(t); ok {
+				f.parent[t] = p
+			}
+		}
+	}
+}
+
+// GetCost returns the cost from t to the nearest goal, or false if t can't
+// reach one.
+func (f *FlowField) GetCost(t *Tile) (float32, bool) {
+	cost, ok := f.cost[t]
+	return cost, ok
+}
+
+// GetNext returns the tile to move to from t, which is the first of the
+// cheapest adjacent tiles. It's nil if t is a goal or can't reach one.
+// A blocked tile still points the way off it.
+func (f *FlowField) GetNext(t *Tile) *Tile {
+	if slices.Contains(f.goals, t) && !t.blocked {
+		return nil
+	}
+
+	var next *Tile
+	var best float32
+	for _, adj := range f.grid.Neighbors(t) {
+		cost, ok := f.cost[adj]
+		if !ok {
+			continue
+		}
+		if cost += f.grid.Cost(t, adj); next == nil || cost < best {
+			next = adj
+			best = cost
+		}
+	}
+
+	return next
+}
+
+// GetDirection returns the unit vector to move along from t, or zero if
+// there's no tile to move to.
+func (f *FlowField) GetDirection(t *Tile) math.Vector2 {
+	next := f.GetNext(t)
+	if next == nil {
+		return math.ZeroVector2
+	}
+
+	return next.GetPosition().Sub(t.GetPosition()).Normalize()
+}
+
+// Block updates the field after t is blocked. Only the tiles that moved
+// through t are recomputed.
+func (f *FlowField) Block(t *Tile) {
+	// Find everything downstream of t
+	// (in a fixed order, so ties always break the same way)
+	affected := []*Tile{t}
+	for i := 0; i < len(affected); i++ {
+		n := affected[i]
+		for _, adj := range n.adjacent {
+			if f.parent[adj] == n {
+				affected = append(affected, adj)
+			}
+		}
+	}
+	for _, n := range affected {
+		delete(f.cost, n)
+		delete(f.parent, n)
+	}
+
+	// Start again from the edge of what's left
+	openSet := search.NewPriorityQueue[*Tile](len(affected))
+	for _, n := range affected {
+		if n.blocked {
+			continue
+		}
+		for _, adj := range f.grid.Neighbors(n) {
+			cost, ok := f.cost[adj]
+			if !ok {
+				continue
+			}
+			cost += f.grid.Cost(n, adj)
+			if old, ok := f.cost[n]; !ok || cost < old {
+				f.cost[n] = cost
+				f.parent[n] = adj
+				openSet.Push(n, cost)
+			}
+		}
+	}
+	f.propagate(openSet)
+}
+
+// Unblock updates the field after t is unblocked. Only the tiles that get
+// cheaper through t are recomputed.
+func (f *FlowField) Unblock(t *Tile) {
+	openSet := search.NewPriorityQueue[*Tile](1)
+	for _, goal := range f.goals {
+		if goal == t {
+			f.cost[t] = 0
+			delete(f.parent, t)
+			openSet.Push(t, 0)
+		}
+	}
+
+	if !openSet.Contains(t) {
+		for _, adj := range f.grid.Neighbors(t) {
+			cost, ok := f.cost[adj]
+			if !ok {
+				continue
+			}
+			cost += f.grid.Cost(t, adj)
+			if old, ok := f.cost[t]; !ok || cost < old {
+				f.cost[t] = cost
+				f.parent[t] = adj
+			}
+		}
+		if cost, ok := f.cost[t]; ok {
+			openSet.Push(t, cost)
+		}
+	}
+	f.propagate(openSet)
+}
+
+// propagate runs Dijkstra's algorithm on from the tiles in openSet,
+// lowering the cost of any tile it can.
+func (f *FlowField) propagate(openSet *search.PriorityQueue[*Tile]) {
+	for !openSet.IsEmpty() {
+		n := openSet.Pop()
+		for _, p := range f.grid.Predecessors(n) {
+			cost := f.cost[n] + f.grid.Cost(p, n)
+			if old, ok := f.cost[p]; !ok || cost < old {
+				f.cost[p] = cost
+				f.parent[p] = n
+				openSet.Push(p, cost)
+			}
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package chapter04
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// checkFlowField fails if f doesn't match a flow field built from scratch.
+func checkFlowField(t *testing.T, f *FlowField) {
+	t.Helper()
+
+	want := NewFlowField(f.grid, f.goals...)
+	for _, row := range f.grid.tiles {
+		for _, tile := range row {
+			wantCost, wantOK := want.GetCost(tile)
+			gotCost, gotOK := f.GetCost(tile)
+			if wantOK != gotOK || wantCost != gotCost {
+				t.Fatalf("expected cost %f (%v), got %f (%v)", wantCost, wantOK, gotCost, gotOK)
+			}
+
+			// The next tile must be adjacent, open and one step cheaper
+			next := f.GetNext(tile)
+			if next == nil || tile.blocked {
+				continue
+			}
+			nextCost, _ := f.GetCost(next)
+			if next.blocked || gotCost-nextCost != f.grid.Cost(tile, next) {
+				t.Fatalf("expected next tile to be one step cheaper, got %f then %f", gotCost, nextCost)
+			}
+		}
+	}
+}
+
+func TestFlowField(t *testing.T) {
+	g := newHeadlessGame(t)
+	grid := g.GetGrid()
+	f := grid.GetFlowField()
+
+	// Every tile's cost is the length of the path from it
+	for _, row := range grid.tiles {
+		for _, tile := range row {
+			path, ok := grid.FindPath(tile, grid.GetEndTile())
+			if cost, _ := f.GetCost(tile); !ok || cost != path.Cost {
+				t.Fatalf("expected cost %f, got %f", path.Cost, cost)
+			}
+		}
+	}
+
+	// Following the field from the start leads to the end
+	steps := 0
+	for tile := grid.GetStartTile(); tile != grid.GetEndTile(); tile = f.GetNext(tile) {
+		steps++
+		if steps > grid.numRows*grid.numCols {
+			t.Fatal("expected the field to lead to the end tile")
+		}
+	}
+	if steps != grid.numCols-1 {
+		t.Errorf("expected %d steps along the row, got %d", grid.numCols-1, steps)
+	}
+	if dir := f.GetDirection(grid.GetStartTile()); dir.X != 1 || dir.Y != 0 {
+		t.Errorf("expected to head right from the start, got %v", dir)
+	}
+}
+
+func TestFlowFieldIncremental(t *testing.T) {
+	g := newHeadlessGame(t)
+	grid := g.GetGrid()
+	f := grid.GetFlowField()
+
+	r := rand.New(rand.NewSource(1))
+	var blocked []*Tile
+	for range 40 {
+		tile := grid.tiles[r.Intn(grid.numRows)][r.Intn(grid.numCols)]
+		if tile.blocked {
+			continue
+		}
+		tile.blocked = true
+		f.Block(tile)
+		checkFlowField(t, f)
+		blocked = append(blocked, tile)
+	}
+
+	for _, tile := range blocked {
+		tile.blocked = false
+		f.Unblock(tile)
+		checkFlowField(t, f)
+	}
+}
+
+func TestBuildTowerKeepsPath(t *testing.T) {
+	g := newHeadlessGame(t)
+	grid := g.GetGrid()
+
+	// Wall off the start tile, except for the last one
+	for _, ref := range []tileRef{{2, 0}, {4, 0}, {3, 1}} {
+		grid.selectTile(ref.Row, ref.Col)
+		grid.BuildTower()
+	}
+
+	if !grid.GetTile(2, 0).blocked || !grid.GetTile(4, 0).blocked {
+		t.Error("expected towers next to the start tile")
+	}
+	if grid.GetTile(3, 1).blocked {
+		t.Error("expected the last tower to be refused")
+	}
+	cost, ok := grid.GetFlowField().GetCost(grid.GetStartTile())
+	if !ok {
+		t.Fatal("expected the start tile to still reach the end tile")
+	}
+	checkFlowField(t, grid.GetFlowField())
+
+	// The path shown is the one enemies take from the start, and it's
+	// as short as any
+	path, ok := grid.FindPath(grid.GetStartTile(), grid.GetEndTile())
+	if !ok || path.Cost != cost {
+		t.Fatalf("expected a path with cost %f, got %f", cost, path.Cost)
+	}
+	steps := 0
+	for tile := grid.GetFlowField().GetNext(grid.GetStartTile()); tile != grid.GetEndTile(); tile = grid.GetFlowField().GetNext(tile) {
+		if tile.GetTileState() != PathTile {
+			t.Errorf("expected path tile %d to be shown", steps+1)
+		}
+		steps++
+	}
+	if steps != len(path.Nodes)-2 {
+		t.Errorf("expected %d path tiles, got %d", len(path.Nodes)-2, steps)
+	}
+}
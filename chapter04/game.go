@@ -2,17 +2,49 @@ package chapter04
 
 import (
 	"slices"
+	"time"
 
+	"github.com/ishtaka/go-game-programming/chapter04/broadphase"
 	"github.com/ishtaka/go-game-programming/chapter04/math"
+	"github.com/ishtaka/go-game-programming/chapter04/math/rand"
 	"github.com/veandco/go-sdl2/img"
 	"github.com/veandco/go-sdl2/sdl"
 )
 
+// DefaultTickRate is the number of simulation steps per second.
+const DefaultTickRate = 60
+
+// saveFileName is where F5 saves the game, and F9 loads it from.
+const saveFileName = "chapter04.sav"
+
+// collisionCellSize is the size of the grid cells circles are sorted into.
+const collisionCellSize = 128
+
+// maxFrameTime is the most time simulated in a single frame (in seconds).
+const maxFrameTime = 0.25
+
 type Game struct {
-	window     *sdl.Window
-	renderer   *sdl.Renderer
-	ticksCount uint64
-	isRunning  bool
+	window      *sdl.Window
+	renderer    *sdl.Renderer
+	inputSystem *InputSystem
+	rng         *rand.RNG
+	clock       Clock
+	lastTime    time.Duration
+	isRunning   bool
+	// Run the simulation without a window or renderer
+	headless bool
+
+	// Time between simulation steps (in seconds)
+	fixedDeltaTime float32
+	// Time not yet simulated
+	accumulator float32
+	// Fraction of a step to interpolate drawing by
+	alpha float32
+
+	// Input being recorded, or played back in place of real input
+	recording   *Recording
+	replay      *Recording
+	replayIndex int
 
 	textures map[string]*sdl.Texture
 	sprites  []Sprite
@@ -21,21 +53,48 @@ type Game struct {
 	pendingActors  []Actor
 	updatingActors bool
 
+	// Circle components sorted by position, for collision queries
+	collisions      *broadphase.Grid[CircleComponent]
+	collisionSystem *CollisionSystem
+
 	// Game-specific
+	// Level to play instead of the level file, if set
+	level     *Level
 	enemies   []*Enemy
 	grid      *Grid
 	nextEnemy float32
 }
 
-func NewGame() *Game {
+func NewGame(clock Clock) *Game {
 	return &Game{
-		ticksCount: 0,
-		textures:   make(map[string]*sdl.Texture),
-		isRunning:  true,
+		clock:           clock,
+		rng:             rand.NewRNG(uint64(time.Now().UnixNano())),
+		inputSystem:     NewInputSystem(),
+		fixedDeltaTime:  1.0 / DefaultTickRate,
+		collisions:      broadphase.NewGrid[CircleComponent](collisionCellSize),
+		collisionSystem: NewCollisionSystem(),
+		textures:        make(map[string]*sdl.Texture),
+		isRunning:       true,
 	}
 }
 
+// NewHeadlessGame creates a game that simulates without a window or renderer.
+// Use Step to advance the simulation.
+func NewHeadlessGame(clock Clock) *Game {
+	g := NewGame(clock)
+	g.headless = true
+
+	return g
+}
+
 func (g *Game) Initialize() error {
+	if g.headless {
+		g.loadData()
+		g.savePreviousTransforms()
+		g.lastTime = g.clock.Now()
+		return nil
+	}
+
 	if err := sdl.Init(sdl.INIT_EVERYTHING); err != nil {
 		sdl.Log("unable to initialize SDL: %s\n", err)
 		return err
@@ -61,77 +120,201 @@ func (g *Game) Initialize() error {
 		return err
 	}
 
+	// Initialize input system
+	if err = g.inputSystem.Initialize(); err != nil {
+		sdl.Log("failed to initialize input system: %s\n", err)
+		return err
+	}
+
 	g.loadData()
+	g.savePreviousTransforms()
 
-	g.ticksCount = sdl.GetTicks64()
+	g.lastTime = g.clock.Now()
 
 	return nil
 }
 
 func (g *Game) RunLoop() {
 	for g.isRunning {
-		g.processInput()
+		g.RunFrame()
+	}
+}
+
+// RunFrame runs a single iteration of the game loop, simulating
+// the time passed on the clock since the previous frame.
+func (g *Game) RunFrame() {
+	if g.replay != nil {
+		g.replayFrame()
+	} else {
+		if !g.headless {
+			g.processInput()
+		}
 		g.update()
+	}
+	if !g.headless {
 		g.generateOutput()
 	}
 }
 
+// Step advances the simulation by deltaTime seconds,
+// without reading input or drawing.
+func (g *Game) Step(deltaTime float32) {
+	g.updateGame(deltaTime)
+}
+
+// SetTickRate sets the number of simulation steps per second.
+func (g *Game) SetTickRate(ticksPerSecond float32) {
+	g.fixedDeltaTime = 1.0 / ticksPerSecond
+}
+
+// SetLevel sets the level to play instead of the level file.
+// Call it before Initialize.
+func (g *Game) SetLevel(level *Level) {
+	g.level = level
+}
+
+// GetTickRate returns the number of simulation steps per second.
+func (g *Game) GetTickRate() float32 {
+	return 1.0 / g.fixedDeltaTime
+}
+
 func (g *Game) processInput() {
+	g.inputSystem.PrepareForUpdate()
+
 	for event := sdl.PollEvent(); event != nil; event = sdl.PollEvent() {
 		switch event.(type) {
 		case *sdl.QuitEvent:
 			g.isRunning = false
-			return
+		default:
+			g.inputSystem.ProcessEvent(event)
 		}
+	}
 
-		state := sdl.GetKeyboardState()
-		if state[sdl.SCANCODE_ESCAPE] != 0 {
-			g.isRunning = false
-			return
-		}
+	g.inputSystem.Update()
+	g.handleInput()
+}
 
-		if state[sdl.SCANCODE_B] != 0 {
-			g.grid.BuildTower()
-		}
+func (g *Game) handleInput() {
+	state := g.inputSystem.GetState()
 
-		// Process mouse
-		x, y, s := sdl.GetMouseState()
-		if s&sdl.ButtonLMask() != 0 {
-			g.grid.ProcessClick(int(x), int(y))
-		}
+	if state.Keyboard.GetKeyValue(sdl.SCANCODE_ESCAPE) {
+		g.isRunning = false
+		return
+	}
+
+	if state.Keyboard.GetKeyState(sdl.SCANCODE_B) == Pressed {
+		g.grid.BuildTower()
+	}
 
-		g.updatingActors = true
-		for _, a := range g.actors {
-			a.ProcessInput(state)
+	// Quick save/load
+	if state.Keyboard.GetKeyState(sdl.SCANCODE_F5) == Pressed {
+		if err := g.SaveGame(saveFileName); err != nil {
+			sdl.Log("failed to save game: %s", err)
 		}
-		g.updatingActors = false
 	}
+	if state.Keyboard.GetKeyState(sdl.SCANCODE_F9) == Pressed {
+		if err := g.LoadGame(saveFileName); err != nil {
+			sdl.Log("failed to load game: %s", err)
+		}
+	}
+
+	// Process mouse
+	if state.Mouse.GetButtonState(sdl.BUTTON_LEFT) == Pressed {
+		pos := state.Mouse.GetPosition()
+		g.grid.ProcessClick(int(pos.X), int(pos.Y))
+	}
+
+	g.updatingActors = true
+	for _, a := range g.actors {
+		a.ProcessInput(state)
+	}
+	g.updatingActors = false
 }
 
 func (g *Game) update() {
-	// SDL_TICKS_PASSED is not available in go-sdl2
-	for {
-		if sdl.GetTicks64() > g.ticksCount+16 {
-			break
+	// Time elapsed since last frame (converted to seconds)
+	now := g.clock.Now()
+	frameTime := float32((now - g.lastTime).Seconds())
+	g.lastTime = now
+
+	if g.recording != nil {
+		g.recording.AddFrame(frameTime, g.inputSystem.GetState())
+	}
+
+	g.simulate(frameTime)
+}
+
+// simulate runs as many fixed steps as fit in frameTime,
+// and any time left over is carried to the next frame.
+func (g *Game) simulate(frameTime float32) {
+	// Clamp the frame time, so a slow frame can't demand more and more
+	// steps to catch up (spiral of death)
+	if frameTime > maxFrameTime {
+		frameTime = maxFrameTime
+	}
+
+	// Simulate in fixed steps until the accumulated time is used up
+	g.accumulator += frameTime
+	for g.accumulator >= g.fixedDeltaTime {
+		g.updateGame(g.fixedDeltaTime)
+		g.accumulator -= g.fixedDeltaTime
+	}
+
+	// How far we are between the last step and the next one
+	g.alpha = g.accumulator / g.fixedDeltaTime
+}
+
+// replayFrame plays the next frame of the replay,
+// using its input and frame time instead of real ones.
+func (g *Game) replayFrame() {
+	if !g.headless {
+		// Keep the window responsive
+		for event := sdl.PollEvent(); event != nil; event = sdl.PollEvent() {
+			if _, ok := event.(*sdl.QuitEvent); ok {
+				g.isRunning = false
+			}
 		}
 	}
 
-	deltaTime := float32(sdl.GetTicks64()-g.ticksCount) / 1000.0
-	if deltaTime > 0.05 {
-		deltaTime = 0.05
+	if g.replayIndex >= len(g.replay.Frames) {
+		g.isRunning = false
+		return
 	}
+	frame := &g.replay.Frames[g.replayIndex]
+	g.replayIndex++
 
-	g.ticksCount = sdl.GetTicks64()
+	g.inputSystem.PrepareForUpdate()
+	g.inputSystem.SetState(&frame.Input)
+	g.handleInput()
+
+	g.simulate(frame.FrameTime)
+}
+
+func (g *Game) updateGame(deltaTime float32) {
+	// Keep transforms from before this update to interpolate drawing from
+	g.savePreviousTransforms()
 
 	// Update all actors
 	g.updatingActors = true
 	for _, a := range g.actors {
 		a.Update(deltaTime)
 	}
+
+	// Pending actors don't update, so their circles are still wherever
+	// they were when they were added. Move them to where they are now.
+	for _, pending := range g.pendingActors {
+		if cc, ok := GetComponent[CircleComponent](pending); ok {
+			g.collisions.Update(cc, cc.GetCenter(), cc.GetRadius())
+		}
+	}
+
+	// Let actors know about what they've run into
+	g.collisionSystem.Update(g.collisions)
 	g.updatingActors = false
 
 	// Move any pending actors to actors
 	for _, pending := range g.pendingActors {
+		pending.SavePreviousTransform()
 		g.actors = append(g.actors, pending)
 	}
 	g.pendingActors = nil
@@ -145,7 +328,13 @@ func (g *Game) update() {
 	}
 	// Delete dead actors (which removes them from actors)
 	for _, deadActor := range deadActors {
-		deadActor.Destroy()
+		g.DestroyActor(deadActor)
+	}
+}
+
+func (g *Game) savePreviousTransforms() {
+	for _, a := range g.actors {
+		a.SavePreviousTransform()
 	}
 }
 
@@ -157,20 +346,32 @@ func (g *Game) generateOutput() {
 
 	// Draw all sprite components
 	for _, sprite := range g.sprites {
-		sprite.Draw(g.renderer)
+		sprite.Draw(g.renderer, g.alpha)
 	}
 
 	g.renderer.Present()
 }
 
 func (g *Game) loadData() {
-	g.grid = NewGrid(g)
+	var err error
+	level := g.level
+	if level == nil {
+		if level, err = LoadLevel(levelFileName); err != nil {
+			sdl.Log("failed to load level %s: %s\n", levelFileName, err)
+			level = DefaultLevel()
+		}
+	}
+
+	if g.grid, err = NewGridFromLevel(g, level); err != nil {
+		sdl.Log("failed to load level %s: %s\n", levelFileName, err)
+		g.grid = NewGrid(g)
+	}
 }
 
 func (g *Game) unloadData() {
 	// Delete actors
 	for len(g.actors) > 0 {
-		g.actors[0].Destroy()
+		g.DestroyActor(g.actors[0])
 	}
 
 	// Destroy textures
@@ -185,6 +386,11 @@ func (g *Game) GetTexture(fileName string) *sdl.Texture {
 		return tex
 	}
 
+	// There is no renderer to create textures with
+	if g.headless {
+		return nil
+	}
+
 	// Load from file
 	surf, err := img.Load("chapter04/" + fileName)
 	if err != nil {
@@ -207,6 +413,11 @@ func (g *Game) GetTexture(fileName string) *sdl.Texture {
 
 func (g *Game) Shutdown() (err error) {
 	defer sdl.Quit()
+	defer func() {
+		if g.inputSystem != nil {
+			g.inputSystem.Shutdown()
+		}
+	}()
 	defer func() {
 		if g.window != nil {
 			err = g.window.Destroy()
@@ -224,6 +435,56 @@ func (g *Game) Shutdown() (err error) {
 	return
 }
 
+// StartRecording records the input of every frame from now on.
+// Call it before Initialize, so the recording starts with the game.
+func (g *Game) StartRecording(seed uint64) {
+	g.rng.Seed(seed)
+	g.recording = NewRecording(seed, g.GetTickRate())
+}
+
+// StopRecording stops recording and returns what was recorded.
+func (g *Game) StopRecording() *Recording {
+	rec := g.recording
+	g.recording = nil
+	return rec
+}
+
+// StartReplay plays a recording back in place of input and the clock,
+// until it runs out of frames. Call it before Initialize.
+func (g *Game) StartReplay(rec *Recording) {
+	g.rng.Seed(rec.Seed)
+	g.SetTickRate(rec.TickRate)
+	g.replay = rec
+	g.replayIndex = 0
+}
+
+func (g *Game) IsReplaying() bool {
+	return g.replay != nil && g.replayIndex < len(g.replay.Frames)
+}
+
+// GetRNG returns the game's random number generator.
+// Seed it before Initialize to make the game repeatable.
+func (g *Game) GetRNG() *rand.RNG {
+	return g.rng
+}
+
+func (g *Game) GetClock() Clock {
+	return g.clock
+}
+
+func (g *Game) IsRunning() bool {
+	return g.isRunning
+}
+
+func (g *Game) GetActors() []Actor {
+	return g.actors
+}
+
+// GetCollisionGrid returns the circle components in the game, sorted by position.
+func (g *Game) GetCollisionGrid() *broadphase.Grid[CircleComponent] {
+	return g.collisions
+}
+
 func (g *Game) AddActor(actor Actor) {
 	// If we're updating actors, need to add to pending
 	if g.updatingActors {
@@ -231,9 +492,14 @@ func (g *Game) AddActor(actor Actor) {
 	} else {
 		g.actors = append(g.actors, actor)
 	}
+
+	actor.OnAdded()
 }
 
+// RemoveActor takes the actor out of the game without destroying it.
 func (g *Game) RemoveActor(actor Actor) {
+	count := len(g.pendingActors) + len(g.actors)
+
 	g.pendingActors = slices.DeleteFunc(g.pendingActors, func(a Actor) bool {
 		return a == actor
 	})
@@ -241,6 +507,38 @@ func (g *Game) RemoveActor(actor Actor) {
 	g.actors = slices.DeleteFunc(g.actors, func(a Actor) bool {
 		return a == actor
 	})
+
+	if len(g.pendingActors)+len(g.actors) < count {
+		actor.OnRemoved()
+	}
+}
+
+// DestroyActor removes the actor and all of its components from the game.
+func (g *Game) DestroyActor(actor Actor) {
+	actor.OnDestroy()
+
+	// Remove components, highest update order first
+	for components := actor.GetComponents(); len(components) > 0; components = actor.GetComponents() {
+		actor.RemoveComponent(components[len(components)-1])
+	}
+
+	g.RemoveActor(actor)
+}
+
+// addComponent registers a component the game keeps track of, such as a sprite
+func (g *Game) addComponent(c Component) {
+	switch c := c.(type) {
+	case Sprite:
+		g.AddSprite(c)
+	}
+}
+
+// removeComponent unregisters a component added with addComponent
+func (g *Game) removeComponent(c Component) {
+	switch c := c.(type) {
+	case Sprite:
+		g.RemoveSprite(c)
+	}
 }
 
 func (g *Game) AddSprite(s Sprite) {
@@ -282,20 +580,14 @@ func (g *Game) RemoveEnemy(enemy *Enemy) {
 }
 
 func (g *Game) GetNearestEnemy(pos math.Vector2) *Enemy {
-	var best *Enemy
-
-	if len(g.enemies) > 0 {
-		best = g.enemies[0]
-		// Save the distance squared of first enemy, and test if others are closer
-		bestDistSq := pos.Sub(best.GetPosition()).LengthSq()
-		for i := 0; i < len(g.enemies); i++ {
-			newDistSq := pos.Sub(g.enemies[i].GetPosition()).LengthSq()
-			if newDistSq < bestDistSq {
-				bestDistSq = newDistSq
-				best = g.enemies[i]
-			}
-		}
+	// Search outward from pos through the collision grid
+	c, ok := g.collisions.Nearest(pos, func(c CircleComponent) bool {
+		_, ok := c.GetOwner().(*Enemy)
+		return ok
+	})
+	if !ok {
+		return nil
 	}
 
-	return best
+	return c.GetOwner().(*Enemy)
 }
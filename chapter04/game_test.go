@@ -0,0 +1,159 @@
+package chapter04
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/ishtaka/go-game-programming/chapter04/math"
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+func newHeadlessGame(t *testing.T) *Game {
+	t.Helper()
+
+	return newLevelGame(t, DefaultLevel())
+}
+
+// stepUntil steps g until done returns true, and fails if a minute of
+// game time goes by first.
+func stepUntil(t *testing.T, g *Game, done func() bool) {
+	t.Helper()
+
+	const deltaTime = 1.0 / 60.0
+	for range 3600 {
+		if done() {
+			return
+		}
+		g.Step(deltaTime)
+	}
+	t.Fatal("timed out")
+}
+
+func TestHeadlessEnemiesFollowPath(t *testing.T) {
+	g := newHeadlessGame(t)
+
+	const deltaTime = 1.0 / 60.0
+	for range 3000 {
+		g.Step(deltaTime)
+
+		for _, e := range g.GetEnemies() {
+			pos := e.GetPosition()
+			if pos.X < 0 || pos.X > 1024 || pos.Y < 160 || pos.Y > 640 {
+				t.Fatalf("enemy left the grid: %v", pos)
+			}
+		}
+	}
+
+	// An enemy spawns every 1.5 seconds and takes about 6 seconds
+	// to reach the base, so only a few are alive at any time.
+	n := len(g.GetEnemies())
+	if n == 0 || n > 5 {
+		t.Errorf("unexpected number of enemies alive: %d", n)
+	}
+}
+
+func TestEnemiesRerouteAroundTower(t *testing.T) {
+	g := newHeadlessGame(t)
+	grid := g.GetGrid()
+
+	var nc NavComponent
+	stepUntil(t, g, func() bool {
+		if enemies := g.GetEnemies(); len(enemies) > 0 {
+			nc, _ = GetComponent[NavComponent](enemies[0])
+		}
+		return nc != nil && nc.GetNextNode() != nil && nc.GetNextNode() != grid.GetStartTile()
+	})
+
+	// Build right where the first enemy is heading
+	next := nc.GetNextNode()
+	ref, _ := grid.getTileRef(next)
+	grid.selectTile(ref.Row, ref.Col)
+	grid.BuildTower()
+	if !next.blocked {
+		t.Fatal("expected a tower to be built")
+	}
+
+	const deltaTime = 1.0 / 60.0
+	for range 600 {
+		for _, e := range g.GetEnemies() {
+			nc, _ := GetComponent[NavComponent](e)
+			if n := nc.GetNextNode(); n != nil && n.blocked {
+				t.Fatalf("expected enemies to steer around the tower")
+			}
+		}
+		g.Step(deltaTime)
+	}
+}
+
+func TestTowerCantEncloseEnemy(t *testing.T) {
+	g := newHeadlessGame(t)
+	grid := g.GetGrid()
+
+	// Wait for the first enemy to get partway along the path
+	var e *Enemy
+	stepUntil(t, g, func() bool {
+		if enemies := g.GetEnemies(); len(enemies) > 0 {
+			e = enemies[0]
+		}
+		return e != nil && grid.GetTileAt(e.GetPosition()) == grid.GetTile(3, 4)
+	})
+
+	// Build all around it
+	for _, ref := range []tileRef{{2, 4}, {4, 4}, {3, 3}, {3, 5}} {
+		grid.selectTile(ref.Row, ref.Col)
+		grid.BuildTower()
+	}
+	if !grid.GetTile(2, 4).blocked || !grid.GetTile(4, 4).blocked || !grid.GetTile(3, 3).blocked {
+		t.Fatal("expected towers to be built next to the enemy")
+	}
+	if grid.GetTile(3, 5).blocked {
+		t.Fatal("expected the last tower to be refused")
+	}
+
+	// It carries on out the open side
+	stepUntil(t, g, func() bool {
+		return !slices.Contains(g.GetEnemies(), e) || grid.GetTileAt(e.GetPosition()) == grid.GetTile(3, 6)
+	})
+}
+
+func TestReplayBuildsTower(t *testing.T) {
+	rec := NewRecording(1, DefaultTickRate)
+
+	var state InputState
+	// Click a tile next to the path
+	state.Mouse.mousePos = math.Vector2{X: 96, Y: 200}
+	state.Mouse.currButtons = sdl.Button(sdl.BUTTON_LEFT)
+	rec.AddFrame(1.0/60.0, &state)
+
+	// Then press B to build on it
+	state.Mouse.currButtons = 0
+	rec.AddFrame(1.0/60.0, &state)
+	state.Keyboard.currState[sdl.SCANCODE_B] = 1
+	for range 10 {
+		rec.AddFrame(1.0/60.0, &state)
+	}
+
+	g := NewHeadlessGame(NewManualClock())
+	g.StartReplay(rec)
+	if err := g.Initialize(); err != nil {
+		t.Fatalf("failed to initialize headless game: %s", err)
+	}
+	t.Cleanup(func() {
+		_ = g.Shutdown()
+	})
+
+	for g.IsRunning() {
+		g.RunFrame()
+	}
+
+	towers := 0
+	for _, a := range g.GetActors() {
+		if _, ok := a.(*Tower); ok {
+			towers++
+		}
+	}
+	// Holding B only builds once
+	if towers != 1 {
+		t.Errorf("expected 1 tower, got %d", towers)
+	}
+}
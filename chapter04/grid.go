@@ -1,10 +1,11 @@
 package chapter04
 
 import (
-	"cmp"
-	"slices"
+	"errors"
+	"unicode/utf8"
 
 	"github.com/ishtaka/go-game-programming/chapter04/math"
+	"github.com/ishtaka/go-game-programming/chapter04/search"
 )
 
 type Grid struct {
@@ -21,41 +22,80 @@ type Grid struct {
 	startY float32
 	// Width/height of each tile
 	tileSize float32
-	// Time between enemies
+	// Time between enemies, if there are no waves
 	enemyTime float32
+	// Where enemies come from, and where they go
+	startTiles, endTiles []*Tile
+	// Enemies to send, the wave being sent, and how many of it have been
+	// (or how many in all, if there are no waves)
+	waves   []Wave
+	wave    int
+	spawned int
+	// Hash of the level the grid was made from
+	levelHash string
+	// Leads enemies to the end tiles
+	flowField *FlowField
 }
 
+// NewGrid creates the default grid.
 func NewGrid(game *Game) *Grid {
+	g, _ := NewGridFromLevel(game, DefaultLevel())
+	return g
+}
+
+// NewGridFromLevel creates a grid laid out as in level, or returns an
+// error if the level isn't valid.
+func NewGridFromLevel(game *Game, level *Level) (*Grid, error) {
+	if err := level.Validate(); err != nil {
+		return nil, err
+	}
+
 	g := &Grid{
 		Actor:     NewActor(game),
-		numRows:   7,
-		numCols:   16,
-		startY:    192,
-		tileSize:  64,
-		enemyTime: 1.5,
+		numRows:   len(level.Tiles),
+		numCols:   utf8.RuneCountInString(level.Tiles[0]),
+		startY:    level.StartY,
+		tileSize:  level.TileSize,
+		enemyTime: level.EnemyTime,
+		waves:     level.Waves,
+		levelHash: level.hash(),
 	}
 
-	// 7 rows, 16 columns
 	g.tiles = make([][]*Tile, g.numRows)
 	for i := 0; i < g.numRows; i++ {
 		g.tiles[i] = make([]*Tile, g.numCols)
 	}
 
 	// Create tiles
-	for i := 0; i < g.numRows; i++ {
-		for j := 0; j < g.numCols; j++ {
-			g.tiles[i][j] = NewTile(game)
-			g.tiles[i][j].SetPosition(math.Vector2{
+	for i, row := range level.Tiles {
+		for j, c := range []rune(row) {
+			t := NewTile(game)
+			t.SetPosition(math.Vector2{
 				X: g.tileSize/2.0 + float32(j)*g.tileSize,
 				Y: g.startY + float32(i)*g.tileSize,
 			})
+			g.tiles[i][j] = t
+
+			def, _ := level.getTileDef(c)
+			t.SetTerrain(def.Terrain)
+			if def.Cost != 0 {
+				t.SetCost(def.Cost)
+			}
+			t.SetTexture(def.Texture)
+			t.blocked = def.Blocked
+
+			// Set start/end tiles
+			if def.Start {
+				t.SetTileState(StartTile)
+				g.startTiles = append(g.startTiles, t)
+			}
+			if def.Base {
+				t.SetTileState(BaseTile)
+				g.endTiles = append(g.endTiles, t)
+			}
 		}
 	}
 
-	// Set start/end tiles
-	g.GetStartTile().SetTileState(StartTile)
-	g.GetEndTile().SetTileState(BaseTile)
-
 	// Set up adjacency tiles
 	for i := 0; i < g.numRows; i++ {
 		for j := 0; j < g.numCols; j++ {
@@ -75,15 +115,19 @@ func NewGrid(game *Game) *Grid {
 		}
 	}
 
-	// Find path (in reverse)
-	g.FindPath(g.GetEndTile(), g.GetStartTile())
-	g.updatePathTile(g.GetStartTile())
+	g.flowField = NewFlowField(g, g.endTiles...)
+	g.replan()
+	g.updatePathTile()
 
-	g.nextEnemy = g.enemyTime
+	if len(g.waves) > 0 {
+		g.nextEnemy = g.waves[0].Delay
+	} else {
+		g.nextEnemy = g.enemyTime
+	}
 
 	game.AddActor(g)
 
-	return g
+	return g, nil
 }
 
 func (g *Grid) Update(deltaTime float32) {
@@ -99,11 +143,59 @@ func (g *Grid) UpdateActor(deltaTime float32) {
 	// Is it time to spawn a new enemy?
 	g.nextEnemy -= deltaTime
 	if g.nextEnemy <= 0.0 {
-		NewEnemy(g.GetGame(), DefaultDrawOrder)
+		g.spawnEnemy()
+	}
+}
+
+// spawnEnemy sends the next enemy, and sets the time until the one after.
+func (g *Grid) spawnEnemy() {
+	if len(g.waves) == 0 {
+		// From each start tile in turn, forever
+		g.newEnemy(g.startTiles[g.spawned%len(g.startTiles)], 0)
+		g.spawned++
 		g.nextEnemy += g.enemyTime
+		return
+	}
+
+	// Have all the waves been sent?
+	if g.wave >= len(g.waves) {
+		return
+	}
+
+	w := &g.waves[g.wave]
+	start := g.startTiles[g.spawned%len(g.startTiles)]
+	if len(w.Starts) > 0 {
+		start = g.startTiles[w.Starts[g.spawned%len(w.Starts)]]
+	}
+	g.newEnemy(start, w.Speed)
+	g.spawned++
+
+	if g.spawned < w.Count {
+		g.nextEnemy += w.Interval
+	} else {
+		// On to the next wave
+		g.wave++
+		g.spawned = 0
+		if g.wave < len(g.waves) {
+			g.nextEnemy += g.waves[g.wave].Delay
+		}
+	}
+}
+
+func (g *Grid) newEnemy(start *Tile, speed float32) {
+	e := NewEnemy(g.GetGame(), DefaultDrawOrder)
+	e.StartAt(start)
+	if nc, ok := GetComponent[NavComponent](e); ok && speed > 0 {
+		nc.SetForwardSpeed(speed)
 	}
 }
 
+// GetWave returns the wave being sent, which is the number of waves if
+// they've all been sent.
+func (g *Grid) GetWave() int {
+	return g.wave
+}
+
 // ProcessClick handles a mouse click at the x/y screen locations.
 func (g *Grid) ProcessClick(x, y int) {
 	y -= int(g.startY - g.tileSize/2)
@@ -117,103 +209,226 @@ func (g *Grid) ProcessClick(x, y int) {
 }
 
 // FindPath uses A* to find a path.
-func (g *Grid) FindPath(start, goal *Tile) bool {
-	for i := 0; i < g.numRows; i++ {
-		for j := 0; j < g.numCols; j++ {
-			g.tiles[i][j].inOpenSet = false
-			g.tiles[i][j].inClosedSet = false
+func (g *Grid) FindPath(start, goal *Tile) (search.Path[*Tile], bool) {
+	return search.FindPath[*Tile](g, start, goal)
+}
+
+// Neighbors returns the adjacent tiles that aren't blocked.
+func (g *Grid) Neighbors(t *Tile) []*Tile {
+	neighbors := make([]*Tile, 0, len(t.adjacent))
+	for _, adj := range t.adjacent {
+		if !adj.blocked {
+			neighbors = append(neighbors, adj)
 		}
 	}
 
-	openSet := make([]*Tile, 0, g.numRows*g.numCols)
-
-	// Set current node to start, and add to closed set
-	current := start
-	current.inClosedSet = true
-
-	for {
-		// Add adjacent nodes to open set
-		for _, neighbor := range current.adjacent {
-			if neighbor.blocked {
-				continue
-			}
+	return neighbors
+}
 
-			// Only check nodes that aren't in the closed set
-			if !neighbor.inClosedSet {
-				if !neighbor.inOpenSet {
-					// Not in the open set, so set parent
-					neighbor.parent = current
-					neighbor.h = neighbor.GetPosition().Sub(goal.GetPosition()).Length()
-					// g(x) is the parent's g plus cost of traversing edge
-					neighbor.g = current.g + g.tileSize
-					neighbor.f = neighbor.g + neighbor.h
-					openSet = append(openSet, neighbor)
-					neighbor.inOpenSet = true
-				} else {
-					// Compute g(x) cost if current becomes the parent
-					newG := current.g + g.tileSize
-					if newG < neighbor.g {
-						// Adopt this node
-						neighbor.parent = current
-						neighbor.g = newG
-						// f(x) changes because g(x) changes
-						neighbor.f = neighbor.g + neighbor.h
-					}
-				}
-			}
-		}
+// Predecessors returns the tiles that can move to t, which are the
+// adjacent tiles that aren't blocked, unless t is blocked. Searching back
+// from the base never reaches a blocked tile, but anything on one can
+// still move off it.
+func (g *Grid) Predecessors(t *Tile) []*Tile {
+	if t.blocked {
+		return nil
+	}
 
-		// If open set is empty, all possible paths are exhausted
-		if len(openSet) == 0 {
-			break
-		}
+	return g.Neighbors(t)
+}
 
-		// Find the lowest cost node in open set
-		lowest := slices.MinFunc(openSet, func(a, b *Tile) int {
-			return cmp.Compare(a.f, b.f)
-		})
-
-		// Set to current and move from open to closed
-		current = lowest
-		openSet = slices.DeleteFunc(openSet, func(t *Tile) bool {
-			return t == lowest
-		})
-		current.inOpenSet = false
-		current.inClosedSet = true
-
-		if current == goal {
-			break
-		}
-	}
+// Cost returns the cost of moving between adjacent tiles, which depends
+// on the tile moved onto.
+func (g *Grid) Cost(from, to *Tile) float32 {
+	return g.tileSize * to.cost
+}
 
-	// Did we find a path?
-	return current == goal
+// Heuristic returns the distance between tiles along the rows and columns.
+func (g *Grid) Heuristic(t, goal *Tile) float32 {
+	return search.Manhattan(t.GetPosition(), goal.GetPosition())
 }
 
 // BuildTower tries to build a tower.
 func (g *Grid) BuildTower() {
 	if g.selectedTile != nil && !g.selectedTile.blocked {
-		g.selectedTile.blocked = true
-		if g.FindPath(g.GetEndTile(), g.GetStartTile()) {
+		g.setTileBlocked(g.selectedTile, true)
+		if g.reachesBase(g.startTiles...) && g.enemiesReachBase() {
 			t := NewTower(g.GetGame())
 			t.SetPosition(g.selectedTile.GetPosition())
+
+			// Enemies find their way around it from where they are
+			for _, e := range g.GetGame().GetEnemies() {
+				if nc, ok := GetComponent[NavComponent](e); ok {
+					nc.Reroute()
+				}
+			}
 		} else {
-			// This tower would block the path, so don't allow build
-			g.selectedTile.blocked = false
-			g.FindPath(g.GetEndTile(), g.GetStartTile())
+			// This tower would block the path, or wall in an enemy,
+			// so don't allow build
+			g.setTileBlocked(g.selectedTile, false)
 		}
-		g.updatePathTile(g.GetStartTile())
+		g.updatePathTile()
 	}
 }
 
-// GetStartTile return start tile.
+// GetStartTile return the first start tile.
 func (g *Grid) GetStartTile() *Tile {
-	return g.tiles[3][0]
+	return g.startTiles[0]
+}
+
+// GetStartTiles returns the start tiles, in reading order.
+func (g *Grid) GetStartTiles() []*Tile {
+	return g.startTiles
+}
+
+// GetFlowField returns the flow field leading to the end tiles.
+func (g *Grid) GetFlowField() *FlowField {
+	return g.flowField
 }
 
-// GetEndTile returns end tile.
+// GetEndTile returns the first end tile.
 func (g *Grid) GetEndTile() *Tile {
-	return g.tiles[3][15]
+	return g.endTiles[0]
+}
+
+// GetEndTiles returns the end tiles, in reading order.
+func (g *Grid) GetEndTiles() []*Tile {
+	return g.endTiles
+}
+
+// GetTileAt returns the tile under a position, or nil if it's off the grid.
+func (g *Grid) GetTileAt(pos math.Vector2) *Tile {
+	col := int(math.Floor(pos.X / g.tileSize))
+	row := int(math.Floor((pos.Y - g.startY + g.tileSize/2) / g.tileSize))
+
+	return g.GetTile(row, col)
+}
+
+// GetTile returns the tile at row/col, or nil if it's off the grid.
+func (g *Grid) GetTile(row, col int) *Tile {
+	if row < 0 || row >= g.numRows || col < 0 || col >= g.numCols {
+		return nil
+	}
+
+	return g.tiles[row][col]
+}
+
+// getTileRef finds the row/col of a tile.
+func (g *Grid) getTileRef(t *Tile) (tileRef, bool) {
+	for i := 0; i < g.numRows; i++ {
+		for j := 0; j < g.numCols; j++ {
+			if g.tiles[i][j] == t {
+				return tileRef{Row: i, Col: j}, true
+			}
+		}
+	}
+
+	return tileRef{}, false
+}
+
+// save returns the blocked tiles, the selection and the enemy timer.
+func (g *Grid) save() gridSave {
+	gs := gridSave{Level: g.levelHash, NextEnemy: g.nextEnemy, Wave: g.wave, Spawned: g.spawned}
+	for i := 0; i < g.numRows; i++ {
+		for j := 0; j < g.numCols; j++ {
+			if g.tiles[i][j].blocked {
+				gs.Blocked = append(gs.Blocked, tileRef{Row: i, Col: j})
+			}
+		}
+	}
+	if g.selectedTile != nil {
+		if ref, ok := g.getTileRef(g.selectedTile); ok {
+			gs.Selected = &ref
+		}
+	}
+
+	return gs
+}
+
+// load restores a state from save, and finds the path through it.
+// The tiles in gs must be on the grid. If the blocked tiles leave no
+// path, the grid is left as it was.
+func (g *Grid) load(gs *gridSave) error {
+	if gs.Wave < 0 || gs.Wave > len(g.waves) || gs.Spawned < 0 {
+		return errors.New("wave is not in the level")
+	}
+
+	prev := g.save()
+	g.setBlocked(gs.Blocked)
+	if !g.replan() {
+		g.setBlocked(prev.Blocked)
+		g.replan()
+		return errors.New("blocked tiles leave no path to a base")
+	}
+	g.updatePathTile()
+
+	if g.selectedTile != nil {
+		g.selectedTile.ToggleSelect()
+		g.selectedTile = nil
+	}
+	if gs.Selected != nil {
+		g.selectTile(gs.Selected.Row, gs.Selected.Col)
+	}
+
+	g.nextEnemy = gs.NextEnemy
+	g.wave = gs.Wave
+	g.spawned = gs.Spawned
+
+	return nil
+}
+
+// setBlocked blocks exactly the given tiles.
+func (g *Grid) setBlocked(blocked []tileRef) {
+	for i := 0; i < g.numRows; i++ {
+		for j := 0; j < g.numCols; j++ {
+			g.tiles[i][j].blocked = false
+		}
+	}
+	for _, ref := range blocked {
+		g.tiles[ref.Row][ref.Col].blocked = true
+	}
+}
+
+// setTileBlocked blocks or unblocks one tile, and updates the flow field
+// around it.
+func (g *Grid) setTileBlocked(t *Tile, blocked bool) {
+	t.blocked = blocked
+	if blocked {
+		g.flowField.Block(t)
+	} else {
+		g.flowField.Unblock(t)
+	}
+}
+
+// replan recomputes the flow field from scratch, as after many tiles change.
+// It returns whether every start tile can reach an end tile.
+func (g *Grid) replan() bool {
+	g.flowField.Rebuild()
+
+	return g.reachesBase(g.startTiles...)
+}
+
+// reachesBase returns whether every one of tiles can reach an end tile.
+func (g *Grid) reachesBase(tiles ...*Tile) bool {
+	for _, t := range tiles {
+		if _, ok := g.flowField.GetCost(t); !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// enemiesReachBase returns whether every enemy on the grid can reach an
+// end tile from the tile it's on.
+func (g *Grid) enemiesReachBase() bool {
+	for _, e := range g.GetGame().GetEnemies() {
+		if t := g.GetTileAt(e.GetPosition()); t != nil && !g.reachesBase(t) {
+			return false
+		}
+	}
+
+	return true
 }
 
 // selectTile selects a specific tile.
@@ -230,24 +445,23 @@ func (g *Grid) selectTile(row, col int) {
 }
 
 // updatePathTile updates textures for tiles on path.
-func (g *Grid) updatePathTile(start *Tile) {
+func (g *Grid) updatePathTile() {
 	// Reset all tiles to normal (except for start/end)
-	for i := 0; i < g.numRows; i++ {
-		for j := 0; j < g.numCols; j++ {
-			if !(i == 3 && j == 0) && !(i == 3 && j == 15) {
-				g.tiles[i][j].SetTileState(DefaultTile)
+	for _, row := range g.tiles {
+		for _, t := range row {
+			if t.GetTileState() == PathTile {
+				t.SetTileState(DefaultTile)
 			}
 		}
 	}
 
-	t := start.parent
-	for t != g.GetEndTile() {
-		t.SetTileState(PathTile)
-		t = t.parent
+	// Show the way enemies go from each start tile
+	for _, start := range g.startTiles {
+		for t := g.flowField.GetNext(start); t != nil; t = g.flowField.GetNext(t) {
+			// Paths can cross other start/end tiles
+			if t.GetTileState() == DefaultTile {
+				t.SetTileState(PathTile)
+			}
+		}
 	}
 }
-
-func (g *Grid) Destroy() {
-	g.GetGame().RemoveActor(g)
-	g.DestroyComponents()
-}
@@ -0,0 +1,193 @@
+package chapter04
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"unicode/utf8"
+)
+
+// levelVersion is the version of the level files read by this code.
+const levelVersion = 1
+
+// levelFileName is the level the game starts with.
+const levelFileName = "Assets/Level.json"
+
+// TileDef is what a character in a level's tile map stands for.
+type TileDef struct {
+	Terrain string `json:"terrain"`
+	// Cost of moving onto the tile, in tiles. It must be at least 1,
+	// and 0 means 1.
+	Cost float32 `json:"cost,omitempty"`
+	// Texture when the tile isn't on the path or selected, if not the default
+	Texture string `json:"texture,omitempty"`
+	Blocked bool   `json:"blocked,omitempty"`
+	Start   bool   `json:"start,omitempty"`
+	Base    bool   `json:"base,omitempty"`
+}
+
+// Wave is a group of enemies sent one after another.
+type Wave struct {
+	// Time before the first enemy, after the last one of the wave before
+	Delay    float32 `json:"delay"`
+	Count    int     `json:"count"`
+	Interval float32 `json:"interval"`
+	// Start tiles the enemies come from in turn, in reading order.
+	// Empty means all of them.
+	Starts []int `json:"starts,omitempty"`
+	// Enemy speed, or 0 for the default
+	Speed float32 `json:"speed,omitempty"`
+}
+
+// Level describes a grid and the enemies sent across it.
+type Level struct {
+	Version int `json:"version"`
+	// Start y position of top left corner
+	StartY float32 `json:"startY"`
+	// Width/height of each tile
+	TileSize float32 `json:"tileSize"`
+	// One string per row, with a character for each tile
+	Tiles []string `json:"tiles"`
+	// Characters to add to the default legend, or to replace in it
+	Legend map[string]TileDef `json:"legend,omitempty"`
+	// Time between enemies, if there are no waves
+	EnemyTime float32 `json:"enemyTime"`
+	Waves     []Wave  `json:"waves,omitempty"`
+}
+
+// defaultLegend is what the characters in a tile map stand for, unless
+// the level says otherwise.
+var defaultLegend = map[string]TileDef{
+	".": {Terrain: "grass"},
+	"#": {Terrain: "rock", Blocked: true},
+	"S": {Terrain: "grass", Start: true},
+	"B": {Terrain: "grass", Base: true},
+}
+
+// DefaultLevel returns a grid of 7 rows and 16 columns, with the start and
+// the base halfway down either side, and an enemy every 1.5 seconds.
+func DefaultLevel() *Level {
+	return &Level{
+		Version:  levelVersion,
+		StartY:   192,
+		TileSize: 64,
+		Tiles: []string{
+			"................",
+			"................",
+			"................",
+			"S..............B",
+			"................",
+			"................",
+			"................",
+		},
+		EnemyTime: 1.5,
+	}
+}
+
+// LoadLevel reads a level from a file.
+func LoadLevel(fileName string) (*Level, error) {
+	f, err := os.Open("chapter04/" + fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ReadLevel(f)
+}
+
+// ReadLevel reads a level as JSON, and checks it can be played.
+func ReadLevel(r io.Reader) (*Level, error) {
+	var l Level
+	if err := json.NewDecoder(r).Decode(&l); err != nil {
+		return nil, err
+	}
+	if l.Version != levelVersion {
+		return nil, fmt.Errorf("level version %d is not supported", l.Version)
+	}
+	if err := l.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &l, nil
+}
+
+// Validate checks the level can be built into a grid.
+func (l *Level) Validate() error {
+	if l.TileSize <= 0 {
+		return errors.New("tile size must be more than 0")
+	}
+	if len(l.Tiles) == 0 {
+		return errors.New("level has no tiles")
+	}
+	for key, def := range l.Legend {
+		if utf8.RuneCountInString(key) != 1 {
+			return fmt.Errorf("legend key %q must be a single character", key)
+		}
+		if def.Cost != 0 && def.Cost < 1 {
+			return fmt.Errorf("cost of %q must be at least 1", key)
+		}
+		if def.Blocked && (def.Start || def.Base) {
+			return fmt.Errorf("start or base %q can't be blocked", key)
+		}
+	}
+
+	cols := utf8.RuneCountInString(l.Tiles[0])
+	starts, bases := 0, 0
+	for i, row := range l.Tiles {
+		if utf8.RuneCountInString(row) != cols {
+			return fmt.Errorf("row %d has %d tiles, not %d", i, utf8.RuneCountInString(row), cols)
+		}
+		for _, c := range row {
+			def, ok := l.getTileDef(c)
+			if !ok {
+				return fmt.Errorf("row %d has unknown tile %q", i, c)
+			}
+			if def.Start {
+				starts++
+			}
+			if def.Base {
+				bases++
+			}
+		}
+	}
+	if starts == 0 || bases == 0 {
+		return errors.New("level needs at least one start tile and one base tile")
+	}
+
+	if len(l.Waves) == 0 && l.EnemyTime <= 0 {
+		return errors.New("enemy time must be more than 0 if there are no waves")
+	}
+	for i, w := range l.Waves {
+		if w.Count <= 0 || w.Interval < 0 || w.Delay < 0 {
+			return fmt.Errorf("wave %d needs a count, and no negative times", i)
+		}
+		for _, s := range w.Starts {
+			if s < 0 || s >= starts {
+				return fmt.Errorf("wave %d has no start tile %d", i, s)
+			}
+		}
+	}
+
+	return nil
+}
+
+// hash identifies the level, so a save can be checked against it.
+func (l *Level) hash() string {
+	// Map keys are encoded in order, so the same level always hashes the same
+	data, _ := json.Marshal(l)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// getTileDef looks up a character in the level's legend, then the default.
+func (l *Level) getTileDef(c rune) (TileDef, bool) {
+	if def, ok := l.Legend[string(c)]; ok {
+		return def, true
+	}
+	def, ok := defaultLegend[string(c)]
+	return def, ok
+}
@@ -0,0 +1,189 @@
+package chapter04
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// newLevelGame starts a headless game on the given level.
+func newLevelGame(t *testing.T, level *Level) *Game {
+	t.Helper()
+
+	g := NewHeadlessGame(NewManualClock())
+	g.SetLevel(level)
+	if err := g.Initialize(); err != nil {
+		t.Fatalf("failed to initialize headless game: %s", err)
+	}
+	t.Cleanup(func() {
+		_ = g.Shutdown()
+	})
+
+	return g
+}
+
+// readLevel reads a level written in a test.
+func readLevel(t *testing.T, level string) *Level {
+	t.Helper()
+
+	l, err := ReadLevel(strings.NewReader(level))
+	if err != nil {
+		t.Fatalf("failed to read level: %s", err)
+	}
+
+	return l
+}
+
+func TestLoadLevel(t *testing.T) {
+	// The game runs from the root of the repository
+	_, file, _, _ := runtime.Caller(0)
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(filepath.Join(filepath.Dir(file), "..")); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	l, err := LoadLevel(levelFileName)
+	if err != nil {
+		t.Fatalf("failed to load %s: %s", levelFileName, err)
+	}
+	if !reflect.DeepEqual(l, DefaultLevel()) {
+		t.Errorf("expected %s to be the default level", levelFileName)
+	}
+}
+
+func TestReadLevel(t *testing.T) {
+	tests := map[string]string{
+		"version":       `{"version": 2, "tileSize": 64, "tiles": ["SB"], "enemyTime": 1}`,
+		"tile size":     `{"version": 1, "tiles": ["SB"], "enemyTime": 1}`,
+		"no tiles":      `{"version": 1, "tileSize": 64, "enemyTime": 1}`,
+		"ragged":        `{"version": 1, "tileSize": 64, "tiles": ["SB", "."], "enemyTime": 1}`,
+		"unknown tile":  `{"version": 1, "tileSize": 64, "tiles": ["S?B"], "enemyTime": 1}`,
+		"no base":       `{"version": 1, "tileSize": 64, "tiles": ["S.."], "enemyTime": 1}`,
+		"cheap tile":    `{"version": 1, "tileSize": 64, "tiles": ["S~B"], "legend": {"~": {"cost": 0.5}}, "enemyTime": 1}`,
+		"blocked start": `{"version": 1, "tileSize": 64, "tiles": ["XB"], "legend": {"X": {"start": true, "blocked": true}}, "enemyTime": 1}`,
+		"no enemies":    `{"version": 1, "tileSize": 64, "tiles": ["SB"]}`,
+		"wave start":    `{"version": 1, "tileSize": 64, "tiles": ["SB"], "waves": [{"count": 1, "starts": [1]}]}`,
+	}
+	for name, level := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ReadLevel(strings.NewReader(level)); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestGridFromLevel(t *testing.T) {
+	g := newLevelGame(t, readLevel(t, `{
+		"version": 1,
+		"startY": 192,
+		"tileSize": 64,
+		"tiles": [
+			"S..~..B",
+			".#.~#..",
+			"S..~..B"
+		],
+		"legend": {"~": {"terrain": "mud", "cost": 3}},
+		"enemyTime": 1.5
+	}`))
+	grid := g.GetGrid()
+
+	if len(grid.GetStartTiles()) != 2 || len(grid.GetEndTiles()) != 2 {
+		t.Fatalf("expected 2 start and 2 end tiles, got %d and %d", len(grid.GetStartTiles()), len(grid.GetEndTiles()))
+	}
+	if grid.GetEndTiles()[1] != grid.GetTile(2, 6) || grid.GetEndTiles()[1].GetTileState() != BaseTile {
+		t.Error("expected the second end tile to be the bottom right base")
+	}
+	if !grid.GetTile(1, 1).blocked || !grid.GetTile(1, 4).blocked {
+		t.Error("expected the rocks to be blocked")
+	}
+	if mud := grid.GetTile(1, 3); mud.GetTerrain() != "mud" || mud.GetCost() != 3 {
+		t.Errorf("expected mud costing 3, got %q costing %f", mud.GetTerrain(), mud.GetCost())
+	}
+
+	// Five grass tiles and one mud tile to either base
+	f := grid.GetFlowField()
+	for _, start := range grid.GetStartTiles() {
+		if cost, _ := f.GetCost(start); cost != 8*64 {
+			t.Errorf("expected a start tile to cost %d, got %f", 8*64, cost)
+		}
+	}
+	checkFlowField(t, f)
+
+	// Walling off one base still leaves the other
+	grid.selectTile(0, 5)
+	grid.BuildTower()
+	grid.selectTile(1, 6)
+	grid.BuildTower()
+	if !grid.GetTile(0, 5).blocked || !grid.GetTile(1, 6).blocked {
+		t.Fatal("expected towers to be built")
+	}
+	if cost, _ := f.GetCost(grid.GetStartTile()); cost != 10*64 {
+		t.Errorf("expected the top start tile to cost %d, got %f", 10*64, cost)
+	}
+	checkFlowField(t, f)
+	for _, start := range grid.GetStartTiles() {
+		end := start
+		for next := f.GetNext(start); next != nil; next = f.GetNext(next) {
+			end = next
+		}
+		if end != grid.GetTile(2, 6) {
+			t.Error("expected every path to lead to the bottom right base")
+		}
+	}
+}
+
+func TestWaves(t *testing.T) {
+	g := newLevelGame(t, readLevel(t, `{
+		"version": 1,
+		"startY": 192,
+		"tileSize": 64,
+		"tiles": [
+			"S.....B",
+			".......",
+			"S.....B"
+		],
+		"waves": [
+			{"delay": 1, "count": 3, "interval": 0.5, "starts": [1]},
+			{"delay": 2, "count": 2, "interval": 0.5, "speed": 300}
+		]
+	}`))
+	grid := g.GetGrid()
+
+	const deltaTime = 1.0 / 60.0
+	seen := make(map[*Enemy]bool)
+	var starts []*Tile
+	for range 600 {
+		g.Step(deltaTime)
+		for _, e := range g.GetEnemies() {
+			if seen[e] {
+				continue
+			}
+			seen[e] = true
+			for _, start := range grid.GetStartTiles() {
+				if e.GetPosition().Sub(start.GetPosition()).Length() <= 10 {
+					starts = append(starts, start)
+				}
+			}
+		}
+	}
+
+	top, bottom := grid.GetStartTiles()[0], grid.GetStartTiles()[1]
+	want := []*Tile{bottom, bottom, bottom, top, bottom}
+	if !reflect.DeepEqual(starts, want) {
+		t.Errorf("expected enemies from start tiles %v, got %v", want, starts)
+	}
+	if grid.GetWave() != 2 {
+		t.Errorf("expected both waves to be sent, got %d", grid.GetWave())
+	}
+	if n := len(g.GetEnemies()); n != 0 {
+		t.Errorf("expected every enemy to reach a base, got %d left", n)
+	}
+}
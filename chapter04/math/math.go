@@ -30,6 +30,18 @@ func (a Angle) Degrees() float64 {
 	return float64(a / Degree)
 }
 
+// Lerp returns angle interpolated from a to b by f, along the shortest arc
+func (a Angle) Lerp(b Angle, f float32) Angle {
+	diff := math.Mod(float64(b-a), 2*math.Pi)
+	if diff > math.Pi {
+		diff -= 2 * math.Pi
+	} else if diff < -math.Pi {
+		diff += 2 * math.Pi
+	}
+
+	return a + Angle(diff*float64(f))
+}
+
 func NearZero(value float32) bool {
 	const epsilon float64 = 0.001
 	return math.Abs(float64(value)) <= epsilon
@@ -92,3 +104,7 @@ func Sqrt(value float32) float32 {
 func Fmod(a, b float32) float32 {
 	return float32(math.Mod(float64(a), float64(b)))
 }
+
+func Floor(value float32) float32 {
+	return float32(math.Floor(float64(value)))
+}
@@ -69,8 +69,3 @@ func (m *moveComponent) GetForwardSpeed() float32 {
 func (m *moveComponent) SetForwardSpeed(speed float32) {
 	m.forwardSpeed = speed
 }
-
-func (m *moveComponent) Destroy() {
-	owner := m.GetOwner()
-	owner.RemoveComponent(m)
-}
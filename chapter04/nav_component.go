@@ -6,11 +6,17 @@ type NavComponent interface {
 	MoveComponent
 	StartPath(start *Tile)
 	TurnTo(pos math.Vector2)
+	GetNextNode() *Tile
+	SetNextNode(node *Tile)
+	GetFlowField() *FlowField
+	SetFlowField(field *FlowField)
+	Reroute()
 }
 
 type navComponent struct {
 	MoveComponent
-	nextNode *Tile
+	nextNode  *Tile
+	flowField *FlowField
 }
 
 func NewNavComponent(owner Actor, updateOrder int) NavComponent {
@@ -26,17 +32,23 @@ func (m *navComponent) Update(deltaTime float32) {
 	if m.nextNode != nil {
 		diff := m.GetOwner().GetPosition().Sub(m.nextNode.GetPosition())
 		if diff.Length() < 2.0 {
-			m.nextNode = m.nextNode.GetParent()
-			m.TurnTo(m.nextNode.GetPosition())
+			// Carry on to wherever the flow field points, if anywhere
+			if next := m.flowField.GetNext(m.nextNode); next != nil {
+				m.nextNode = next
+				m.TurnTo(m.nextNode.GetPosition())
+			}
 		}
 	}
 
 	m.MoveComponent.Update(deltaTime)
 }
 
+// StartPath heads to the tile the flow field points to from start.
 func (m *navComponent) StartPath(start *Tile) {
-	m.nextNode = start.GetParent()
-	m.TurnTo(m.nextNode.GetPosition())
+	m.nextNode = m.flowField.GetNext(start)
+	if m.nextNode != nil {
+		m.TurnTo(m.nextNode.GetPosition())
+	}
 }
 
 func (m *navComponent) TurnTo(pos math.Vector2) {
@@ -48,7 +60,47 @@ func (m *navComponent) TurnTo(pos math.Vector2) {
 	m.GetOwner().SetRotation(angle)
 }
 
-func (m *navComponent) Destroy() {
-	owner := m.GetOwner()
-	owner.RemoveComponent(m)
+// GetNextNode returns the tile being moved to, or nil.
+func (m *navComponent) GetNextNode() *Tile {
+	return m.nextNode
+}
+
+// SetNextNode moves to node, then follows the flow field on from it.
+func (m *navComponent) SetNextNode(node *Tile) {
+	m.nextNode = node
+}
+
+// GetFlowField returns the flow field being followed.
+func (m *navComponent) GetFlowField() *FlowField {
+	return m.flowField
+}
+
+// SetFlowField sets the flow field to follow from tile to tile.
+func (m *navComponent) SetFlowField(field *FlowField) {
+	m.flowField = field
+}
+
+// Reroute checks the tile being moved to is still on the way, as after a
+// tower is built. If not, it picks the way on from the tile the owner is on.
+func (m *navComponent) Reroute() {
+	if m.nextNode != nil && !m.nextNode.blocked {
+		if _, ok := m.flowField.GetCost(m.nextNode); ok {
+			return
+		}
+	}
+
+	current := m.GetOwner().GetGame().GetGrid().GetTileAt(m.GetOwner().GetPosition())
+	if current == nil {
+		return
+	}
+	if current.blocked || current == m.nextNode {
+		// Step off it
+		m.nextNode = m.flowField.GetNext(current)
+	} else {
+		// Back to the middle of it first, so as not to cut across a tower
+		m.nextNode = current
+	}
+	if m.nextNode != nil {
+		m.TurnTo(m.nextNode.GetPosition())
+	}
 }
@@ -0,0 +1,298 @@
+package chapter04
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ishtaka/go-game-programming/chapter04/math"
+	"github.com/ishtaka/go-game-programming/chapter04/math/rand"
+)
+
+// saveVersion is the version of the save files written by this code.
+// Bump it when the format changes, and register a migration from the old version.
+const saveVersion = 2
+
+// Migration upgrades a decoded save file from one version to the next.
+// It edits the JSON objects in place; the version number is updated by the caller.
+type Migration func(save map[string]any) error
+
+var migrations = map[int]Migration{
+	1: migrateLevel,
+}
+
+// RegisterMigration sets the migration that upgrades saves of version to version+1.
+func RegisterMigration(version int, m Migration) {
+	migrations[version] = m
+}
+
+type saveData struct {
+	Version int `json:"version"`
+	// Time not yet simulated, and the state of the random streams
+	Accumulator float32 `json:"accumulator"`
+	RNG         []byte  `json:"rng"`
+
+	Grid    gridSave     `json:"grid"`
+	Towers  []towerSave  `json:"towers"`
+	Enemies []enemySave  `json:"enemies"`
+	Bullets []bulletSave `json:"bullets"`
+}
+
+type tileRef struct {
+	Row int `json:"row"`
+	Col int `json:"col"`
+}
+
+type gridSave struct {
+	// Hash of the level the save was made on
+	Level     string    `json:"level"`
+	Blocked   []tileRef `json:"blocked"`
+	Selected  *tileRef  `json:"selected,omitempty"`
+	NextEnemy float32   `json:"nextEnemy"`
+	// Wave being sent, and how many of it have been
+	Wave    int `json:"wave,omitempty"`
+	Spawned int `json:"spawned,omitempty"`
+}
+
+type towerSave struct {
+	Position   math.Vector2 `json:"position"`
+	Rotation   math.Angle   `json:"rotation"`
+	NextAttack float32      `json:"nextAttack"`
+}
+
+type enemySave struct {
+	Position math.Vector2 `json:"position"`
+	Rotation math.Angle   `json:"rotation"`
+	// Tile the enemy is heading to
+	NextNode *tileRef `json:"nextNode,omitempty"`
+	Speed    float32  `json:"speed,omitempty"`
+}
+
+type bulletSave struct {
+	Position math.Vector2 `json:"position"`
+	Rotation math.Angle   `json:"rotation"`
+	LiveTime float32      `json:"liveTime"`
+}
+
+// SaveGame writes the game in progress to a file.
+func (g *Game) SaveGame(fileName string) error {
+	f, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := g.WriteSaveGame(f); err != nil {
+		return err
+	}
+
+	return f.Close()
+}
+
+// LoadGame restores a game saved with SaveGame.
+func (g *Game) LoadGame(fileName string) error {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return g.ReadSaveGame(f)
+}
+
+// WriteSaveGame encodes the grid, towers, enemies, bullets and timers as JSON.
+func (g *Game) WriteSaveGame(w io.Writer) error {
+	rng, err := g.rng.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	save := saveData{
+		Version:     saveVersion,
+		Accumulator: g.accumulator,
+		RNG:         rng,
+		Grid:        g.grid.save(),
+	}
+
+	for _, a := range g.actors {
+		switch a := a.(type) {
+		case *Tower:
+			save.Towers = append(save.Towers, towerSave{
+				Position:   a.GetPosition(),
+				Rotation:   a.GetRotation(),
+				NextAttack: a.nextAttack,
+			})
+		case *Enemy:
+			es := enemySave{
+				Position: a.GetPosition(),
+				Rotation: a.GetRotation(),
+			}
+			if nc, ok := GetComponent[NavComponent](a); ok {
+				es.Speed = nc.GetForwardSpeed()
+				if nc.GetNextNode() != nil {
+					if ref, ok := g.grid.getTileRef(nc.GetNextNode()); ok {
+						es.NextNode = &ref
+					}
+				}
+			}
+			save.Enemies = append(save.Enemies, es)
+		case *Bullet:
+			save.Bullets = append(save.Bullets, bulletSave{
+				Position: a.GetPosition(),
+				Rotation: a.GetRotation(),
+				LiveTime: a.liveTime,
+			})
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	return enc.Encode(&save)
+}
+
+// ReadSaveGame replaces the grid, towers, enemies, bullets and timers
+// with a saved game, migrating it from an older version if needed.
+func (g *Game) ReadSaveGame(r io.Reader) error {
+	var doc map[string]any
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return err
+	}
+
+	if err := migrate(doc); err != nil {
+		return err
+	}
+
+	// Decode the migrated document into the current format
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	var save saveData
+	if err := json.Unmarshal(data, &save); err != nil {
+		return err
+	}
+
+	return g.restore(&save)
+}
+
+// migrate upgrades a decoded save file to saveVersion.
+func migrate(doc map[string]any) error {
+	v, ok := doc["version"].(float64)
+	if !ok {
+		return errors.New("save has no version")
+	}
+
+	version := int(v)
+	if version > saveVersion {
+		return fmt.Errorf("save version %d is newer than %d", version, saveVersion)
+	}
+
+	for ; version < saveVersion; version++ {
+		m, ok := migrations[version]
+		if !ok {
+			return fmt.Errorf("no migration from save version %d", version)
+		}
+		if err := m(doc); err != nil {
+			return fmt.Errorf("migrating save version %d: %w", version, err)
+		}
+		doc["version"] = version + 1
+	}
+
+	return nil
+}
+
+// migrateLevel adds the level to version 1 saves. They don't say which
+// level they were made on, so they're taken to be on the default one.
+func migrateLevel(save map[string]any) error {
+	grid, ok := save["grid"].(map[string]any)
+	if !ok {
+		return errors.New("save has no grid")
+	}
+	grid["level"] = DefaultLevel().hash()
+
+	return nil
+}
+
+func (g *Game) restore(save *saveData) error {
+	// Check everything first, so a bad save leaves the game as it was
+	if save.Grid.Level != g.grid.levelHash {
+		return errors.New("save is from a different level")
+	}
+
+	rng := rand.NewRNG(0)
+	if err := rng.UnmarshalBinary(save.RNG); err != nil {
+		return err
+	}
+
+	refs := save.Grid.Blocked
+	if save.Grid.Selected != nil {
+		refs = append(refs, *save.Grid.Selected)
+	}
+	for _, e := range save.Enemies {
+		if e.NextNode != nil {
+			refs = append(refs, *e.NextNode)
+		}
+	}
+	for _, ref := range refs {
+		if g.grid.GetTile(ref.Row, ref.Col) == nil {
+			return fmt.Errorf("tile %d,%d is not on the grid", ref.Row, ref.Col)
+		}
+	}
+
+	if err := g.grid.load(&save.Grid); err != nil {
+		return err
+	}
+
+	// Copied, so anything holding the game's RNG sees the new state
+	*g.rng = *rng
+	g.accumulator = save.Accumulator
+
+	// Remove what's in play now
+	var old []Actor
+	for _, a := range g.actors {
+		switch a.(type) {
+		case *Tower, *Enemy, *Bullet:
+			old = append(old, a)
+		}
+	}
+	for _, a := range old {
+		g.DestroyActor(a)
+	}
+
+	for _, ts := range save.Towers {
+		t := NewTower(g)
+		t.SetPosition(ts.Position)
+		t.SetRotation(ts.Rotation)
+		t.nextAttack = ts.NextAttack
+	}
+
+	for _, es := range save.Enemies {
+		e := NewEnemy(g, DefaultDrawOrder)
+		e.SetPosition(es.Position)
+		e.SetRotation(es.Rotation)
+		if nc, ok := GetComponent[NavComponent](e); ok {
+			var next *Tile
+			if es.NextNode != nil {
+				next = g.grid.GetTile(es.NextNode.Row, es.NextNode.Col)
+			}
+			nc.SetNextNode(next)
+			if es.Speed > 0 {
+				nc.SetForwardSpeed(es.Speed)
+			}
+		}
+	}
+
+	for _, bs := range save.Bullets {
+		b := NewBullet(g, DefaultDrawOrder)
+		b.SetPosition(bs.Position)
+		b.SetRotation(bs.Rotation)
+		b.liveTime = bs.LiveTime
+	}
+
+	// Don't interpolate from where things were before loading
+	g.savePreviousTransforms()
+
+	return nil
+}
@@ -0,0 +1,181 @@
+package chapter04
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func countActors[T Actor](g *Game) int {
+	n := 0
+	for _, a := range g.GetActors() {
+		if _, ok := a.(T); ok {
+			n++
+		}
+	}
+	return n
+}
+
+func TestSaveGameRoundTrip(t *testing.T) {
+	g := newHeadlessGame(t)
+	g.GetGrid().selectTile(2, 3)
+	g.GetGrid().BuildTower()
+	g.GetGrid().selectTile(4, 6)
+	g.GetGrid().BuildTower()
+	for range 240 {
+		g.Step(1.0 / 60.0)
+	}
+
+	var buf bytes.Buffer
+	if err := g.WriteSaveGame(&buf); err != nil {
+		t.Fatalf("failed to write save: %s", err)
+	}
+
+	loaded := newHeadlessGame(t)
+	if err := loaded.ReadSaveGame(&buf); err != nil {
+		t.Fatalf("failed to read save: %s", err)
+	}
+
+	if n := countActors[*Tower](loaded); n != 2 {
+		t.Errorf("expected 2 towers, got %d", n)
+	}
+	if !loaded.GetGrid().GetTile(2, 3).blocked || !loaded.GetGrid().GetTile(4, 6).blocked {
+		t.Errorf("expected tower tiles to be blocked")
+	}
+	if loaded.GetGrid().nextEnemy != g.GetGrid().nextEnemy {
+		t.Errorf("expected next enemy in %f, got %f", g.GetGrid().nextEnemy, loaded.GetGrid().nextEnemy)
+	}
+	if countActors[*Bullet](loaded) != countActors[*Bullet](g) {
+		t.Errorf("expected %d bullets, got %d", countActors[*Bullet](g), countActors[*Bullet](loaded))
+	}
+
+	enemies, loadedEnemies := g.GetEnemies(), loaded.GetEnemies()
+	if len(enemies) == 0 || len(enemies) != len(loadedEnemies) {
+		t.Fatalf("expected %d enemies, got %d", len(enemies), len(loadedEnemies))
+	}
+	for i, e := range enemies {
+		if e.GetPosition() != loadedEnemies[i].GetPosition() {
+			t.Errorf("expected enemy at %v, got %v", e.GetPosition(), loadedEnemies[i].GetPosition())
+		}
+		nc, _ := GetComponent[NavComponent](e)
+		loadedNC, _ := GetComponent[NavComponent](loadedEnemies[i])
+		want, _ := g.GetGrid().getTileRef(nc.GetNextNode())
+		got, _ := loaded.GetGrid().getTileRef(loadedNC.GetNextNode())
+		if want != got {
+			t.Errorf("expected enemy heading to %v, got %v", want, got)
+		}
+	}
+}
+
+func TestSaveGameMigration(t *testing.T) {
+	g := newHeadlessGame(t)
+
+	var buf bytes.Buffer
+	if err := g.WriteSaveGame(&buf); err != nil {
+		t.Fatalf("failed to write save: %s", err)
+	}
+
+	// Pretend an older version kept the enemy timer at the top level
+	var doc map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("failed to decode save: %s", err)
+	}
+	grid := doc["grid"].(map[string]any)
+	delete(grid, "nextEnemy")
+	doc["enemyTimer"] = 0.25
+	doc["version"] = saveVersion - 1
+	old, _ := json.Marshal(doc)
+
+	// Stand in for the real migration from the version before
+	real := migrations[saveVersion-1]
+	delete(migrations, saveVersion-1)
+	t.Cleanup(func() {
+		migrations[saveVersion-1] = real
+	})
+
+	if err := g.ReadSaveGame(bytes.NewReader(old)); err == nil {
+		t.Errorf("expected an error without a migration")
+	}
+
+	RegisterMigration(saveVersion-1, func(save map[string]any) error {
+		save["grid"].(map[string]any)["nextEnemy"] = save["enemyTimer"]
+		delete(save, "enemyTimer")
+		return nil
+	})
+
+	if err := g.ReadSaveGame(bytes.NewReader(old)); err != nil {
+		t.Fatalf("failed to read migrated save: %s", err)
+	}
+	if g.GetGrid().nextEnemy != 0.25 {
+		t.Errorf("expected next enemy in 0.25, got %f", g.GetGrid().nextEnemy)
+	}
+
+	if err := g.ReadSaveGame(strings.NewReader(`{"version": 99}`)); err == nil {
+		t.Errorf("expected an error for a newer version")
+	}
+}
+
+func TestSaveGameBlockedPath(t *testing.T) {
+	g := newHeadlessGame(t)
+
+	var buf bytes.Buffer
+	if err := g.WriteSaveGame(&buf); err != nil {
+		t.Fatalf("failed to write save: %s", err)
+	}
+	var doc map[string]any
+	_ = json.Unmarshal(buf.Bytes(), &doc)
+
+	// Wall off the start tile
+	doc["grid"].(map[string]any)["blocked"] = []map[string]int{
+		{"row": 2, "col": 0}, {"row": 4, "col": 0}, {"row": 3, "col": 1},
+	}
+	data, _ := json.Marshal(doc)
+
+	if err := g.ReadSaveGame(bytes.NewReader(data)); err == nil {
+		t.Fatalf("expected an error for a blocked path")
+	}
+	if g.GetGrid().GetTile(3, 1).blocked {
+		t.Errorf("expected the grid to be left as it was")
+	}
+}
+
+func TestSaveGameLevel(t *testing.T) {
+	g := newHeadlessGame(t)
+	g.GetGrid().selectTile(2, 3)
+	g.GetGrid().BuildTower()
+
+	var buf bytes.Buffer
+	if err := g.WriteSaveGame(&buf); err != nil {
+		t.Fatalf("failed to write save: %s", err)
+	}
+
+	// The same size of grid, with the start tile somewhere else
+	level := DefaultLevel()
+	level.Tiles[3] = ".S.............B"
+	other := newLevelGame(t, level)
+	if err := other.ReadSaveGame(bytes.NewReader(buf.Bytes())); err == nil {
+		t.Errorf("expected an error for a save from another level")
+	}
+	if other.GetGrid().GetTile(2, 3).blocked || countActors[*Tower](other) != 0 {
+		t.Errorf("expected the grid to be left as it was")
+	}
+
+	// Version 1 saves don't say which level they're from
+	var doc map[string]any
+	_ = json.Unmarshal(buf.Bytes(), &doc)
+	delete(doc["grid"].(map[string]any), "level")
+	doc["version"] = 1
+	old, _ := json.Marshal(doc)
+
+	loaded := newHeadlessGame(t)
+	if err := loaded.ReadSaveGame(bytes.NewReader(old)); err != nil {
+		t.Fatalf("failed to read version 1 save: %s", err)
+	}
+	if !loaded.GetGrid().GetTile(2, 3).blocked {
+		t.Errorf("expected the tower tile to be blocked")
+	}
+	if err := other.ReadSaveGame(bytes.NewReader(old)); err == nil {
+		t.Errorf("expected an error for a version 1 save on another level")
+	}
+}
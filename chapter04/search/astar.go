@@ -1,10 +1,5 @@
 package search
 
-import (
-	"cmp"
-	"slices"
-)
-
 type AStarScratch struct {
 	ParentEdge      *WeightedEdge
 	Heuristic       float32
@@ -16,7 +11,7 @@ type AStarScratch struct {
 type AStarMap = map[*WeightedGraphNode]*AStarScratch
 
 func AStar(g *WeightedGraph, start, goal *WeightedGraphNode, outMap AStarMap) bool {
-	openSet := make([]*WeightedGraphNode, 0, len(g.nodes))
+	openSet := NewPriorityQueue[*WeightedGraphNode](len(g.nodes))
 
 	// Set current node to start, and mark in closed set
 	current := start
@@ -33,11 +28,11 @@ func AStar(g *WeightedGraph, start, goal *WeightedGraphNode, outMap AStarMap) bo
 				// Not in the open set, so parent must be current
 				if !data.InOpenSet {
 					data.ParentEdge = edge
-					data.Heuristic = neighbor.Heuristic(goal)
+					data.Heuristic = g.Heuristic(neighbor, goal)
 					// Actual cost is the parent's plus cost of traversing edge
 					data.ActualFromStart = outMap[current].ActualFromStart + edge.weight
 					data.InOpenSet = true
-					openSet = append(openSet, neighbor)
+					openSet.Push(neighbor, data.Heuristic+data.ActualFromStart)
 				} else {
 					// Compute what new actual cost is if current becomes parent
 					newG := outMap[current].ActualFromStart + edge.weight
@@ -45,28 +40,19 @@ func AStar(g *WeightedGraph, start, goal *WeightedGraphNode, outMap AStarMap) bo
 						// Current should adopt this node
 						data.ParentEdge = edge
 						data.ActualFromStart = newG
+						openSet.Push(neighbor, data.Heuristic+data.ActualFromStart)
 					}
 				}
 			}
 		}
 
 		// If open set is empty, all possible paths are exhausted
-		if len(openSet) == 0 {
+		if openSet.IsEmpty() {
 			break
 		}
 
-		// Find the lowest cost node in open set
-		lowest := slices.MinFunc(openSet, func(a, b *WeightedGraphNode) int {
-			fOfA := outMap[a].Heuristic + outMap[a].ActualFromStart
-			fOfB := outMap[b].Heuristic + outMap[b].ActualFromStart
-			return cmp.Compare(fOfA, fOfB)
-		})
-
-		// Set to current and move from open to closed
-		current = lowest
-		openSet = slices.DeleteFunc(openSet, func(n *WeightedGraphNode) bool {
-			return n == lowest
-		})
+		// Move the lowest cost node from open to closed, and set to current
+		current = openSet.Pop()
 		outMap[current].InOpenSet = false
 		outMap[current].InClosedSet = true
 
@@ -1,6 +1,11 @@
 package search
 
-import "testing"
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/ishtaka/go-game-programming/chapter04/math"
+)
 
 func TestAStar(t *testing.T) {
 	g := createWeightedGraph(t)
@@ -14,3 +19,102 @@ func TestAStar(t *testing.T) {
 		t.Error("path not found")
 	}
 }
+
+// shortestCosts finds the cheapest cost from start to every node by
+// relaxing every edge until nothing changes.
+func shortestCosts(g *WeightedGraph, start *WeightedGraphNode) map[*WeightedGraphNode]float32 {
+	costs := map[*WeightedGraphNode]float32{start: 0}
+	for changed := true; changed; {
+		changed = false
+		for _, node := range g.nodes {
+			cost, ok := costs[node]
+			if !ok {
+				continue
+			}
+			for _, edge := range node.edges {
+				if old, ok := costs[edge.to]; !ok || cost+edge.weight < old {
+					costs[edge.to] = cost + edge.weight
+					changed = true
+				}
+			}
+		}
+	}
+
+	return costs
+}
+
+func TestAStarOptimal(t *testing.T) {
+	// Every edge costs at least the distance it covers, so none of the
+	// heuristics overestimate
+	g := newGridGraph(12)
+	r := rand.New(rand.NewSource(1))
+	for _, node := range g.nodes {
+		for _, edge := range node.edges {
+			edge.weight = 1 + 4*r.Float32()
+		}
+	}
+
+	heuristics := map[string]HeuristicFunc{
+		"none":      nil,
+		"manhattan": Manhattan,
+		"euclidean": Euclidean,
+		"octile":    Octile,
+	}
+
+	start := g.nodes[0]
+	want := shortestCosts(g, start)
+	for name, h := range heuristics {
+		g.SetHeuristic(h)
+		for _, goal := range []*WeightedGraphNode{g.nodes[11], g.nodes[77], g.nodes[len(g.nodes)-1]} {
+			m := make(AStarMap, len(g.nodes))
+			for _, node := range g.nodes {
+				m[node] = &AStarScratch{}
+			}
+			if !AStar(g, start, goal, m) {
+				t.Fatalf("%s: path not found", name)
+			}
+
+			path := AStarPath(m, start, goal)
+			if !math.NearZero(path.Cost - want[goal]) {
+				t.Errorf("%s: expected cost %f, got %f", name, want[goal], path.Cost)
+			}
+			var sum float32
+			for _, edge := range path.Edges {
+				sum += edge.Cost
+			}
+			if !math.NearZero(sum - path.Cost) {
+				t.Errorf("%s: expected edges to add up to %f, got %f", name, path.Cost, sum)
+			}
+
+			found, ok := FindPath[*WeightedGraphNode](g, start, goal)
+			if !ok || !math.NearZero(found.Cost-want[goal]) {
+				t.Errorf("%s: expected FindPath cost %f, got %f", name, want[goal], found.Cost)
+			}
+		}
+	}
+}
+
+func TestAStarTakesCheaperDetour(t *testing.T) {
+	// The direct edge is shorter but costs more than going around
+	g := NewWeightedGraph(3)
+	start, detour, goal := NewWeightedGraphNode(2), NewWeightedGraphNode(1), NewWeightedGraphNode(0)
+	start.SetPosition(math.Vector2{X: 0, Y: 0})
+	detour.SetPosition(math.Vector2{X: 1, Y: 1})
+	goal.SetPosition(math.Vector2{X: 2, Y: 0})
+	start.AddEdge(goal, 10)
+	start.AddEdge(detour, 2)
+	detour.AddEdge(goal, 2)
+	g.AddNode(start)
+	g.AddNode(detour)
+	g.AddNode(goal)
+	g.SetHeuristic(Euclidean)
+
+	m := AStarMap{start: {}, detour: {}, goal: {}}
+	if !AStar(g, start, goal, m) {
+		t.Fatal("path not found")
+	}
+	path := AStarPath(m, start, goal)
+	if len(path.Nodes) != 3 || path.Nodes[1] != detour || path.Cost != 4 {
+		t.Errorf("expected the detour with cost 4, got %d nodes with cost %f", len(path.Nodes), path.Cost)
+	}
+}
@@ -0,0 +1,202 @@
+package search
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+var benchSizes = []int{32, 128, 256}
+
+// findPathLinear is FindPath with an unsorted open set, as a baseline for
+// the priority queue.
+func findPathLinear[N comparable](g Searchable[N], start, goal N) bool {
+	scratch := map[N]*pathScratch[N]{start: {inClosedSet: true}}
+	var openSet []N
+
+	for current := start; current != goal; {
+		for _, neighbor := range g.Neighbors(current) {
+			data, ok := scratch[neighbor]
+			if !ok {
+				data = &pathScratch[N]{}
+				scratch[neighbor] = data
+			}
+			if data.inClosedSet {
+				continue
+			}
+
+			newG := scratch[current].actualFromStart + g.Cost(current, neighbor)
+			if !data.inOpenSet {
+				data.heuristic = g.Heuristic(neighbor, goal)
+				data.actualFromStart = newG
+				data.inOpenSet = true
+				openSet = append(openSet, neighbor)
+			} else if newG < data.actualFromStart {
+				data.actualFromStart = newG
+			}
+		}
+
+		if len(openSet) == 0 {
+			return false
+		}
+
+		current = slices.MinFunc(openSet, func(a, b N) int {
+			return cmp.Compare(scratch[a].heuristic+scratch[a].actualFromStart, scratch[b].heuristic+scratch[b].actualFromStart)
+		})
+		openSet = slices.DeleteFunc(openSet, func(n N) bool {
+			return n == current
+		})
+		scratch[current].inOpenSet = false
+		scratch[current].inClosedSet = true
+	}
+
+	return true
+}
+
+// The heuristic is 0, so each search visits the whole grid
+func BenchmarkFindPath(b *testing.B) {
+	for _, l := range benchSizes {
+		g := newGridGraph(l)
+		start, goal := g.nodes[0], g.nodes[len(g.nodes)-1]
+
+		b.Run(fmt.Sprintf("heap/%dx%d", l, l), func(b *testing.B) {
+			for range b.N {
+				if _, ok := FindPath[*WeightedGraphNode](g, start, goal); !ok {
+					b.Fatal("path not found")
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("linear/%dx%d", l, l), func(b *testing.B) {
+			for range b.N {
+				if !findPathLinear[*WeightedGraphNode](g, start, goal) {
+					b.Fatal("path not found")
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkAStar(b *testing.B) {
+	for _, l := range benchSizes {
+		g := newGridGraph(l)
+		b.Run(fmt.Sprintf("%dx%d", l, l), func(b *testing.B) {
+			for range b.N {
+				m := make(AStarMap, len(g.nodes))
+				for _, node := range g.nodes {
+					m[node] = &AStarScratch{}
+				}
+				if !AStar(g, g.nodes[0], g.nodes[len(g.nodes)-1], m) {
+					b.Fatal("path not found")
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkGBFS(b *testing.B) {
+	for _, l := range benchSizes {
+		g := newGridGraph(l)
+		b.Run(fmt.Sprintf("%dx%d", l, l), func(b *testing.B) {
+			for range b.N {
+				m := make(GBFSMap, len(g.nodes))
+				for _, node := range g.nodes {
+					m[node] = &GBFSScratch{}
+				}
+				if !GBFS(g, g.nodes[0], g.nodes[len(g.nodes)-1], m) {
+					b.Fatal("path not found")
+				}
+			}
+		})
+	}
+}
+
+// newWallGrid makes an l x l grid with a wall every 8 columns, and a gap
+// at the bottom or top of each in turn.
+func newWallGrid(l int) *UniformGrid {
+	g := NewUniformGrid(l, l)
+	for x := 4; x < l-1; x += 8 {
+		gap := l - 1
+		if (x/8)%2 == 1 {
+			gap = 0
+		}
+		for y := 0; y < l; y++ {
+			g.SetBlocked(Cell{x, y}, y != gap)
+		}
+	}
+
+	return g
+}
+
+// toWeightedGraph makes a WeightedGraph with the same moves as u.
+func toWeightedGraph(u *UniformGrid) (*WeightedGraph, map[Cell]*WeightedGraphNode) {
+	g := NewWeightedGraph(u.width * u.height)
+	g.SetHeuristic(Octile)
+	nodes := make(map[Cell]*WeightedGraphNode, u.width*u.height)
+	for y := 0; y < u.height; y++ {
+		for x := 0; x < u.width; x++ {
+			c := Cell{x, y}
+			nodes[c] = NewWeightedGraphNode(8)
+			nodes[c].SetPosition(c.toVector2())
+			g.AddNode(nodes[c])
+		}
+	}
+	for c, node := range nodes {
+		for _, n := range u.Neighbors(c) {
+			node.AddEdge(nodes[n], u.Cost(c, n))
+		}
+	}
+
+	return g, nodes
+}
+
+func BenchmarkGridSearch(b *testing.B) {
+	for _, l := range benchSizes {
+		u := newWallGrid(l)
+		start, goal := Cell{0, 0}, Cell{l - 1, l - 1}
+		g, nodes := toWeightedGraph(u)
+
+		b.Run(fmt.Sprintf("astar/%dx%d", l, l), func(b *testing.B) {
+			for range b.N {
+				m := make(AStarMap, len(g.nodes))
+				for _, node := range g.nodes {
+					m[node] = &AStarScratch{}
+				}
+				if !AStar(g, nodes[start], nodes[goal], m) {
+					b.Fatal("path not found")
+				}
+			}
+		})
+
+		finders := []struct {
+			name string
+			find func(g *UniformGrid, start, goal Cell) (Path[Cell], bool)
+		}{
+			{"findpath", func(g *UniformGrid, start, goal Cell) (Path[Cell], bool) {
+				return FindPath[Cell](g, start, goal)
+			}},
+			{"dijkstra", func(g *UniformGrid, start, goal Cell) (Path[Cell], bool) {
+				return Dijkstra[Cell](g, start, goal)
+			}},
+			{"bidirectional", func(g *UniformGrid, start, goal Cell) (Path[Cell], bool) {
+				return BidirectionalAStar[Cell](g, start, goal)
+			}},
+			{"jps", JumpPointSearch},
+		}
+		for _, f := range finders {
+			b.Run(fmt.Sprintf("%s/%dx%d", f.name, l, l), func(b *testing.B) {
+				for range b.N {
+					if _, ok := f.find(u, start, goal); !ok {
+						b.Fatal("path not found")
+					}
+				}
+			})
+		}
+
+		b.Run(fmt.Sprintf("distances/%dx%d", l, l), func(b *testing.B) {
+			for range b.N {
+				DistancesFrom[Cell](u, goal)
+			}
+		})
+	}
+}
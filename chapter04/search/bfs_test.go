@@ -33,6 +33,11 @@ func TestBFS(t *testing.T) {
 
 	m := make(NodeToParentMap, l)
 	if found := BFS(g, g.nodes[0], g.nodes[9], m); !found {
-		t.Error("path not found")
+		t.Fatal("path not found")
+	}
+
+	// 1 down and 4 across
+	if path := BFSPath(m, g.nodes[0], g.nodes[9]); len(path.Nodes) != 6 || path.Cost != 5 {
+		t.Errorf("expected 6 nodes with cost 5, got %d with cost %f", len(path.Nodes), path.Cost)
 	}
 }
@@ -0,0 +1,130 @@
+package search
+
+import "slices"
+
+// Reversible is a graph that can also list the nodes with edges into a node.
+type Reversible[N comparable] interface {
+	Searchable[N]
+	// Predecessors returns the nodes with an edge to n
+	Predecessors(n N) []N
+}
+
+// frontier is one direction of a bidirectional search.
+type frontier[N comparable] struct {
+	scratch map[N]*pathScratch[N]
+	openSet *PriorityQueue[N]
+	// Expands back along edges
+	backward bool
+}
+
+func newFrontier[N comparable](start N, backward bool) *frontier[N] {
+	f := &frontier[N]{
+		scratch:  map[N]*pathScratch[N]{start: {}},
+		openSet:  NewPriorityQueue[N](0),
+		backward: backward,
+	}
+	f.openSet.Push(start, 0)
+
+	return f
+}
+
+// BidirectionalAStar runs A* from start and from goal at once, and stops
+// once the cheapest path where they meet can't be beaten. If g isn't
+// Reversible, its edges must be the same both ways. The heuristic must
+// be the same both ways, and never more than the cost between nodes.
+func BidirectionalAStar[N comparable](g Searchable[N], start, goal N) (Path[N], bool) {
+	if start == goal {
+		return Path[N]{Nodes: []N{start}}, true
+	}
+
+	forward := newFrontier(start, false)
+	backward := newFrontier(goal, true)
+
+	// Cheapest path found so far, through meet
+	var meet N
+	best := float32(-1)
+
+	for !forward.openSet.IsEmpty() && !backward.openSet.IsEmpty() {
+		// Neither side can find anything cheaper than best
+		_, fTop := forward.openSet.Peek()
+		_, bTop := backward.openSet.Peek()
+		if best >= 0 && (fTop >= best || bTop >= best) {
+			break
+		}
+
+		// Expand the side with less to look at
+		current, other, target := forward, backward, goal
+		if backward.openSet.Len() < forward.openSet.Len() {
+			current, other, target = backward, forward, start
+		}
+
+		n := current.openSet.Pop()
+		current.scratch[n].inClosedSet = true
+		current.scratch[n].inOpenSet = false
+
+		for _, neighbor := range current.neighbors(g, n) {
+			data, ok := current.scratch[neighbor]
+			if !ok {
+				data = &pathScratch[N]{heuristic: g.Heuristic(neighbor, target)}
+				current.scratch[neighbor] = data
+			} else if data.inClosedSet {
+				continue
+			}
+
+			newG := current.scratch[n].actualFromStart + current.cost(g, n, neighbor)
+			if ok && data.inOpenSet && newG >= data.actualFromStart {
+				continue
+			}
+			data.parent = n
+			data.actualFromStart = newG
+			data.inOpenSet = true
+			current.openSet.Push(neighbor, newG+data.heuristic)
+
+			// Has the other side been here?
+			if o, ok := other.scratch[neighbor]; ok {
+				if total := newG + o.actualFromStart; best < 0 || total < best {
+					best = total
+					meet = neighbor
+				}
+			}
+		}
+	}
+
+	if best < 0 {
+		return Path[N]{}, false
+	}
+
+	// Start to meet, then meet to goal
+	nodes := []N{meet}
+	for n := meet; n != start; {
+		n = forward.scratch[n].parent
+		nodes = append(nodes, n)
+	}
+	slices.Reverse(nodes)
+	for n := meet; n != goal; {
+		n = backward.scratch[n].parent
+		nodes = append(nodes, n)
+	}
+
+	return newPath(g, nodes), true
+}
+
+func (f *frontier[N]) neighbors(g Searchable[N], n N) []N {
+	if f.backward {
+		if r, ok := g.(Reversible[N]); ok {
+			return r.Predecessors(n)
+		}
+	}
+
+	return g.Neighbors(n)
+}
+
+func (f *frontier[N]) cost(g Searchable[N], from, to N) float32 {
+	if f.backward {
+		if _, ok := g.(Reversible[N]); ok {
+			return g.Cost(to, from)
+		}
+	}
+
+	return g.Cost(from, to)
+}
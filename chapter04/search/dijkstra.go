@@ -0,0 +1,124 @@
+package search
+
+import "slices"
+
+// DistanceMap holds the cheapest cost between every node that can be
+// reached and the nearest source, as found by DistancesFrom or DistancesTo.
+type DistanceMap[N comparable] struct {
+	graph   Searchable[N]
+	dist    map[N]float32
+	parent  map[N]N
+	sources map[N]bool
+	// Costs are to the sources, not from them
+	reverse bool
+}
+
+// DistancesFrom uses Dijkstra's algorithm to find the cost from the
+// nearest of sources to every node.
+func DistancesFrom[N comparable](g Searchable[N], sources ...N) *DistanceMap[N] {
+	return distances(g, false, sources)
+}
+
+// DistancesTo uses Dijkstra's algorithm to find the cost from every node
+// to the nearest of goals, as for a flow field. If g isn't Reversible,
+// its edges must be the same both ways.
+func DistancesTo[N comparable](g Searchable[N], goals ...N) *DistanceMap[N] {
+	return distances(g, true, goals)
+}
+
+func distances[N comparable](g Searchable[N], reverse bool, sources []N) *DistanceMap[N] {
+	d := &DistanceMap[N]{
+		graph:   g,
+		dist:    make(map[N]float32),
+		parent:  make(map[N]N),
+		sources: make(map[N]bool, len(sources)),
+		reverse: reverse,
+	}
+
+	openSet := NewPriorityQueue[N](len(sources))
+	for _, s := range sources {
+		d.dist[s] = 0
+		d.sources[s] = true
+		openSet.Push(s, 0)
+	}
+
+	for !openSet.IsEmpty() {
+		current := openSet.Pop()
+		for _, neighbor := range d.neighbors(current) {
+			newDist := d.dist[current] + d.cost(current, neighbor)
+			if old, ok := d.dist[neighbor]; !ok || newDist < old {
+				d.dist[neighbor] = newDist
+				d.parent[neighbor] = current
+				openSet.Push(neighbor, newDist)
+			}
+		}
+	}
+
+	return d
+}
+
+func (d *DistanceMap[N]) neighbors(n N) []N {
+	if d.reverse {
+		if r, ok := d.graph.(Reversible[N]); ok {
+			return r.Predecessors(n)
+		}
+	}
+
+	return d.graph.Neighbors(n)
+}
+
+// cost returns the cost of the edge found from one node to the next,
+// which runs the other way for DistancesTo.
+func (d *DistanceMap[N]) cost(from, to N) float32 {
+	if d.reverse {
+		return d.graph.Cost(to, from)
+	}
+
+	return d.graph.Cost(from, to)
+}
+
+// GetDistance returns the cost between n and the nearest source, or false
+// if n can't be reached.
+func (d *DistanceMap[N]) GetDistance(n N) (float32, bool) {
+	dist, ok := d.dist[n]
+	return dist, ok
+}
+
+// GetParent returns the node next to n on the cheapest path to the
+// nearest source, or false if n is a source or can't be reached.
+func (d *DistanceMap[N]) GetParent(n N) (N, bool) {
+	p, ok := d.parent[n]
+	return p, ok
+}
+
+// PathTo returns the cheapest path between n and the nearest source,
+// which starts at n for DistancesTo.
+func (d *DistanceMap[N]) PathTo(n N) (Path[N], bool) {
+	if _, ok := d.dist[n]; !ok {
+		return Path[N]{}, false
+	}
+
+	nodes := []N{n}
+	for s := n; !d.sources[s]; {
+		s = d.parent[s]
+		nodes = append(nodes, s)
+	}
+	if !d.reverse {
+		slices.Reverse(nodes)
+	}
+
+	return newPath(d.graph, nodes), true
+}
+
+type zeroHeuristic[N comparable] struct {
+	Searchable[N]
+}
+
+func (zeroHeuristic[N]) Heuristic(_, _ N) float32 {
+	return 0
+}
+
+// Dijkstra finds the cheapest path from start to goal without a heuristic.
+func Dijkstra[N comparable](g Searchable[N], start, goal N) (Path[N], bool) {
+	return FindPath[N](zeroHeuristic[N]{g}, start, goal)
+}
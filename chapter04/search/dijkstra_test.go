@@ -0,0 +1,41 @@
+package search
+
+import "testing"
+
+func TestDistancesFrom(t *testing.T) {
+	// Two sources on a 5x5 grid
+	g := createWeightedGraph(t)
+	d := DistancesFrom[*WeightedGraphNode](g, g.nodes[0], g.nodes[24])
+
+	// Each node is as far as the nearer corner
+	for i, node := range g.nodes {
+		row, col := i/5, i%5
+		want := float32(min(row+col, 8-row-col))
+		if got, ok := d.GetDistance(node); !ok || got != want {
+			t.Errorf("node %d: expected %f, got %f", i, want, got)
+		}
+	}
+
+	if _, ok := d.GetParent(g.nodes[0]); ok {
+		t.Error("expected a source to have no parent")
+	}
+	// Each parent is one step nearer
+	for _, node := range g.nodes[1:24] {
+		p, ok := d.GetParent(node)
+		dn, _ := d.GetDistance(node)
+		dp, _ := d.GetDistance(p)
+		if !ok || dp != dn-1 {
+			t.Errorf("expected parent to be 1 nearer, got %f and %f", dp, dn)
+		}
+	}
+
+	path, ok := d.PathTo(g.nodes[7])
+	if !ok || path.Nodes[0] != g.nodes[0] || path.Cost != 3 {
+		t.Errorf("expected path from the nearest corner with cost 3, got %f", path.Cost)
+	}
+
+	island := NewWeightedGraphNode(0)
+	if _, ok := d.PathTo(island); ok {
+		t.Error("expected no path to an unreachable node")
+	}
+}
@@ -1,10 +1,5 @@
 package search
 
-import (
-	"cmp"
-	"slices"
-)
-
 type GBFSScratch struct {
 	ParentEdge  *WeightedEdge
 	Heuristic   float32
@@ -15,7 +10,7 @@ type GBFSScratch struct {
 type GBFSMap = map[*WeightedGraphNode]*GBFSScratch
 
 func GBFS(g *WeightedGraph, start, goal *WeightedGraphNode, outMap GBFSMap) bool {
-	openSet := make([]*WeightedGraphNode, 0, len(g.nodes))
+	openSet := NewPriorityQueue[*WeightedGraphNode](len(g.nodes))
 
 	// Set current node to start, and mark in closed set
 	current := start
@@ -32,28 +27,20 @@ func GBFS(g *WeightedGraph, start, goal *WeightedGraphNode, outMap GBFSMap) bool
 				data.ParentEdge = edge
 				if !data.InOpenSet {
 					// Compute the heuristic for this node, and add to open set
-					data.Heuristic = edge.to.Heuristic(goal)
+					data.Heuristic = g.Heuristic(edge.to, goal)
 					data.InOpenSet = true
-					openSet = append(openSet, edge.to)
+					openSet.Push(edge.to, data.Heuristic)
 				}
 			}
 		}
 
 		// If open set is empty, all possible paths are exhausted
-		if len(openSet) == 0 {
+		if openSet.IsEmpty() {
 			break
 		}
 
-		// Find the lowest cost node in open set
-		lowest := slices.MinFunc(openSet, func(a, b *WeightedGraphNode) int {
-			return cmp.Compare(outMap[a].Heuristic, outMap[b].Heuristic)
-		})
-
-		// Set to current and move from open to closed
-		current = lowest
-		openSet = slices.DeleteFunc(openSet, func(n *WeightedGraphNode) bool {
-			return n == lowest
-		})
+		// Move the lowest cost node from open to closed, and set to current
+		current = openSet.Pop()
 		outMap[current].InOpenSet = false
 		outMap[current].InClosedSet = true
 
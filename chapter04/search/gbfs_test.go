@@ -11,6 +11,11 @@ func TestGBFS(t *testing.T) {
 	}
 
 	if found := GBFS(g, g.nodes[0], g.nodes[9], m); !found {
-		t.Error("path not found")
+		t.Fatal("path not found")
+	}
+
+	path := GBFSPath(m, g.nodes[0], g.nodes[9])
+	if path.Nodes[0] != g.nodes[0] || path.Nodes[len(path.Nodes)-1] != g.nodes[9] {
+		t.Error("expected path from start to goal")
 	}
 }
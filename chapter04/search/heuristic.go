@@ -0,0 +1,25 @@
+package search
+
+import "github.com/ishtaka/go-game-programming/chapter04/math"
+
+// HeuristicFunc estimates the cost of moving between two positions.
+type HeuristicFunc func(a, b math.Vector2) float32
+
+// Manhattan is the distance moving only along the axes, as on a grid
+// without diagonals.
+func Manhattan(a, b math.Vector2) float32 {
+	return math.Abs(a.X-b.X) + math.Abs(a.Y-b.Y)
+}
+
+// Euclidean is the straight line distance.
+func Euclidean(a, b math.Vector2) float32 {
+	return a.Sub(b).Length()
+}
+
+// Octile is the distance moving along the axes and diagonals, as on a grid
+// with diagonals.
+func Octile(a, b math.Vector2) float32 {
+	dx := math.Abs(a.X - b.X)
+	dy := math.Abs(a.Y - b.Y)
+	return dx + dy + (math.Sqrt(2)-2)*math.Min(dx, dy)
+}
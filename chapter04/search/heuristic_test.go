@@ -0,0 +1,32 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/ishtaka/go-game-programming/chapter04/math"
+)
+
+func TestHeuristics(t *testing.T) {
+	a := math.Vector2{X: 1, Y: 2}
+	b := math.Vector2{X: 4, Y: 6}
+
+	tests := []struct {
+		name string
+		h    HeuristicFunc
+		want float32
+	}{
+		{"manhattan", Manhattan, 7},
+		{"euclidean", Euclidean, 5},
+		// 3 diagonal steps and 1 straight
+		{"octile", Octile, 3*math.Sqrt(2) + 1},
+	}
+
+	for _, tt := range tests {
+		if got := tt.h(a, b); !math.NearZero(got - tt.want) {
+			t.Errorf("%s: expected %f, got %f", tt.name, tt.want, got)
+		}
+		if got := tt.h(b, a); !math.NearZero(got - tt.want) {
+			t.Errorf("%s (swapped): expected %f, got %f", tt.name, tt.want, got)
+		}
+	}
+}
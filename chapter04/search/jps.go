@@ -0,0 +1,187 @@
+package search
+
+import "slices"
+
+// JumpPointSearch is A* for a UniformGrid that skips over cells where the
+// path can only go straight on, and only stops at jump points where it can
+// turn. The path it returns still lists every cell along the way.
+func JumpPointSearch(g *UniformGrid, start, goal Cell) (Path[Cell], bool) {
+	if !g.IsWalkable(start) || !g.IsWalkable(goal) {
+		return Path[Cell]{}, false
+	}
+
+	scratch := map[Cell]*pathScratch[Cell]{start: {inClosedSet: true}}
+	openSet := NewPriorityQueue[Cell](0)
+
+	current := start
+	for current != goal {
+		for _, next := range g.prunedNeighbors(current, scratch[current].parent, current != start) {
+			jp, ok := g.jump(next, current, goal)
+			if !ok {
+				continue
+			}
+
+			data, ok := scratch[jp]
+			if !ok {
+				data = &pathScratch[Cell]{heuristic: g.Heuristic(jp, goal)}
+				scratch[jp] = data
+			} else if data.inClosedSet {
+				continue
+			}
+
+			// Jump points are in a straight or diagonal line from current
+			newG := scratch[current].actualFromStart + g.Heuristic(current, jp)
+			if !data.inOpenSet || newG < data.actualFromStart {
+				data.parent = current
+				data.actualFromStart = newG
+				data.inOpenSet = true
+				openSet.Push(jp, newG+data.heuristic)
+			}
+		}
+
+		if openSet.IsEmpty() {
+			return Path[Cell]{}, false
+		}
+
+		current = openSet.Pop()
+		scratch[current].inOpenSet = false
+		scratch[current].inClosedSet = true
+	}
+
+	// Fill in the cells between jump points
+	nodes := []Cell{goal}
+	for c := goal; c != start; {
+		p := scratch[c].parent
+		dx, dy := sign(p.X-c.X), sign(p.Y-c.Y)
+		for c != p {
+			c = Cell{c.X + dx, c.Y + dy}
+			nodes = append(nodes, c)
+		}
+	}
+	slices.Reverse(nodes)
+
+	return newPath[Cell](g, nodes), true
+}
+
+// prunedNeighbors returns the cells worth jumping towards from c, given
+// the direction it was reached from.
+func (u *UniformGrid) prunedNeighbors(c, parent Cell, hasParent bool) []Cell {
+	if !hasParent {
+		return u.Neighbors(c)
+	}
+
+	var neighbors []Cell
+	add := func(dx, dy int) {
+		neighbors = append(neighbors, Cell{c.X + dx, c.Y + dy})
+	}
+	walkable := func(dx, dy int) bool {
+		return u.IsWalkable(Cell{c.X + dx, c.Y + dy})
+	}
+
+	dx, dy := sign(c.X-parent.X), sign(c.Y-parent.Y)
+	switch {
+	case dx != 0 && dy != 0:
+		// Diagonal: carry on, or go along either axis
+		if walkable(0, dy) {
+			add(0, dy)
+		}
+		if walkable(dx, 0) {
+			add(dx, 0)
+		}
+		if walkable(0, dy) && walkable(dx, 0) {
+			add(dx, dy)
+		}
+	case dx != 0:
+		// Horizontal: carry on, or turn up or down
+		if walkable(dx, 0) {
+			add(dx, 0)
+			if walkable(0, 1) {
+				add(dx, 1)
+			}
+			if walkable(0, -1) {
+				add(dx, -1)
+			}
+		}
+		if walkable(0, 1) {
+			add(0, 1)
+		}
+		if walkable(0, -1) {
+			add(0, -1)
+		}
+	default:
+		// Vertical: carry on, or turn left or right
+		if walkable(0, dy) {
+			add(0, dy)
+			if walkable(1, 0) {
+				add(1, dy)
+			}
+			if walkable(-1, 0) {
+				add(-1, dy)
+			}
+		}
+		if walkable(1, 0) {
+			add(1, 0)
+		}
+		if walkable(-1, 0) {
+			add(-1, 0)
+		}
+	}
+
+	return neighbors
+}
+
+// jump moves from c away from from until it finds the goal or a jump point,
+// or runs into something.
+func (u *UniformGrid) jump(c, from, goal Cell) (Cell, bool) {
+	dx, dy := c.X-from.X, c.Y-from.Y
+	walkable := func(x, y int) bool {
+		return u.IsWalkable(Cell{x, y})
+	}
+
+	for {
+		if !u.IsWalkable(c) {
+			return Cell{}, false
+		}
+		if c == goal {
+			return c, true
+		}
+
+		x, y := c.X, c.Y
+		switch {
+		case dx != 0 && dy != 0:
+			// Stop here if going along either axis finds anything
+			if _, ok := u.jump(Cell{x + dx, y}, c, goal); ok {
+				return c, true
+			}
+			if _, ok := u.jump(Cell{x, y + dy}, c, goal); ok {
+				return c, true
+			}
+		case dx != 0:
+			// A wall behind opens up, so there's a forced neighbor
+			if (walkable(x, y-1) && !walkable(x-dx, y-1)) || (walkable(x, y+1) && !walkable(x-dx, y+1)) {
+				return c, true
+			}
+		default:
+			if (walkable(x-1, y) && !walkable(x-1, y-dy)) || (walkable(x+1, y) && !walkable(x+1, y-dy)) {
+				return c, true
+			}
+		}
+
+		// Diagonals can't cut corners
+		if !walkable(x+dx, y) || !walkable(x, y+dy) {
+			return Cell{}, false
+		}
+		c = Cell{x + dx, y + dy}
+	}
+}
+
+func sign(x int) int {
+	switch {
+	case x > 0:
+		return 1
+	case x < 0:
+		return -1
+	}
+
+	return 0
+}
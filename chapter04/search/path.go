@@ -0,0 +1,69 @@
+package search
+
+import "slices"
+
+// Edge is one step along a path.
+type Edge[N comparable] struct {
+	From, To N
+	Cost     float32
+}
+
+// Path is a search result, from the start node to the goal node.
+type Path[N comparable] struct {
+	Nodes []N
+	Edges []Edge[N]
+	// Sum of the edge costs
+	Cost float32
+}
+
+// buildPath walks back from goal to start. parent returns a node's parent
+// and the cost of the edge from it.
+func buildPath[N comparable](start, goal N, parent func(n N) (N, float32)) Path[N] {
+	path := Path[N]{Nodes: []N{goal}}
+	for n := goal; n != start; {
+		p, cost := parent(n)
+		path.Edges = append(path.Edges, Edge[N]{From: p, To: n, Cost: cost})
+		path.Cost += cost
+		path.Nodes = append(path.Nodes, p)
+		n = p
+	}
+	slices.Reverse(path.Nodes)
+	slices.Reverse(path.Edges)
+
+	return path
+}
+
+// AStarPath returns the path found by AStar.
+func AStarPath(outMap AStarMap, start, goal *WeightedGraphNode) Path[*WeightedGraphNode] {
+	return buildPath(start, goal, func(n *WeightedGraphNode) (*WeightedGraphNode, float32) {
+		edge := outMap[n].ParentEdge
+		return edge.from, edge.weight
+	})
+}
+
+// GBFSPath returns the path found by GBFS.
+func GBFSPath(outMap GBFSMap, start, goal *WeightedGraphNode) Path[*WeightedGraphNode] {
+	return buildPath(start, goal, func(n *WeightedGraphNode) (*WeightedGraphNode, float32) {
+		edge := outMap[n].ParentEdge
+		return edge.from, edge.weight
+	})
+}
+
+// BFSPath returns the path found by BFS, where each edge costs 1.
+func BFSPath(outMap NodeToParentMap, start, goal *GraphNode) Path[*GraphNode] {
+	return buildPath(start, goal, func(n *GraphNode) (*GraphNode, float32) {
+		return outMap[n], 1
+	})
+}
+
+// newPath makes a path through the given nodes, which must be adjacent.
+func newPath[N comparable](g Searchable[N], nodes []N) Path[N] {
+	path := Path[N]{Nodes: nodes}
+	for i := 1; i < len(nodes); i++ {
+		cost := g.Cost(nodes[i-1], nodes[i])
+		path.Edges = append(path.Edges, Edge[N]{From: nodes[i-1], To: nodes[i], Cost: cost})
+		path.Cost += cost
+	}
+
+	return path
+}
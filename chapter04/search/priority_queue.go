@@ -0,0 +1,123 @@
+package search
+
+// PriorityQueue is a binary min-heap, so the element with the lowest
+// priority comes out first. It also tracks where each element is in the heap,
+// so an element's priority can change without searching for it.
+type PriorityQueue[T comparable] struct {
+	elems      []T
+	priorities []float32
+	index      map[T]int
+}
+
+func NewPriorityQueue[T comparable](size int) *PriorityQueue[T] {
+	return &PriorityQueue[T]{
+		elems:      make([]T, 0, size),
+		priorities: make([]float32, 0, size),
+		index:      make(map[T]int, size),
+	}
+}
+
+// Push adds elem, or changes its priority if it's already in the queue.
+func (q *PriorityQueue[T]) Push(elem T, priority float32) {
+	if i, ok := q.index[elem]; ok {
+		old := q.priorities[i]
+		q.priorities[i] = priority
+		if priority < old {
+			q.up(i)
+		} else {
+			q.down(i)
+		}
+		return
+	}
+
+	q.elems = append(q.elems, elem)
+	q.priorities = append(q.priorities, priority)
+	q.index[elem] = len(q.elems) - 1
+	q.up(len(q.elems) - 1)
+}
+
+// Pop removes and returns the element with the lowest priority.
+func (q *PriorityQueue[T]) Pop() T {
+	elem := q.elems[0]
+	last := len(q.elems) - 1
+	q.swap(0, last)
+	q.elems = q.elems[:last]
+	q.priorities = q.priorities[:last]
+	delete(q.index, elem)
+	q.down(0)
+
+	return elem
+}
+
+// Remove takes elem out of the queue, if it's in it.
+func (q *PriorityQueue[T]) Remove(elem T) {
+	i, ok := q.index[elem]
+	if !ok {
+		return
+	}
+
+	last := len(q.elems) - 1
+	q.swap(i, last)
+	q.elems = q.elems[:last]
+	q.priorities = q.priorities[:last]
+	delete(q.index, elem)
+	if i < last {
+		q.down(i)
+		q.up(i)
+	}
+}
+
+// Peek returns the lowest priority, without removing its element.
+func (q *PriorityQueue[T]) Peek() (T, float32) {
+	return q.elems[0], q.priorities[0]
+}
+
+func (q *PriorityQueue[T]) Contains(elem T) bool {
+	_, ok := q.index[elem]
+	return ok
+}
+
+func (q *PriorityQueue[T]) Len() int {
+	return len(q.elems)
+}
+
+func (q *PriorityQueue[T]) IsEmpty() bool {
+	return len(q.elems) == 0
+}
+
+func (q *PriorityQueue[T]) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if q.priorities[parent] <= q.priorities[i] {
+			break
+		}
+		q.swap(i, parent)
+		i = parent
+	}
+}
+
+func (q *PriorityQueue[T]) down(i int) {
+	n := len(q.elems)
+	for {
+		smallest := i
+		left, right := 2*i+1, 2*i+2
+		if left < n && q.priorities[left] < q.priorities[smallest] {
+			smallest = left
+		}
+		if right < n && q.priorities[right] < q.priorities[smallest] {
+			smallest = right
+		}
+		if smallest == i {
+			return
+		}
+		q.swap(i, smallest)
+		i = smallest
+	}
+}
+
+func (q *PriorityQueue[T]) swap(i, j int) {
+	q.elems[i], q.elems[j] = q.elems[j], q.elems[i]
+	q.priorities[i], q.priorities[j] = q.priorities[j], q.priorities[i]
+	q.index[q.elems[i]] = i
+	q.index[q.elems[j]] = j
+}
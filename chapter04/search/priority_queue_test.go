@@ -0,0 +1,35 @@
+package search
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPriorityQueue(t *testing.T) {
+	q := NewPriorityQueue[string](4)
+	q.Push("c", 3)
+	q.Push("a", 1)
+	q.Push("e", 5)
+	q.Push("d", 4)
+	q.Push("b", 2)
+
+	// Decrease and increase keys
+	q.Push("e", 0)
+	q.Push("a", 6)
+	q.Remove("d")
+
+	if !q.Contains("e") || q.Contains("d") {
+		t.Error("expected e but not d to be in the queue")
+	}
+	if elem, priority := q.Peek(); elem != "e" || priority != 0 {
+		t.Errorf("expected e at 0 first, got %s at %f", elem, priority)
+	}
+
+	var got []string
+	for !q.IsEmpty() {
+		got = append(got, q.Pop())
+	}
+	if want := []string{"e", "b", "c", "a"}; !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
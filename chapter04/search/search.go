@@ -1,5 +1,7 @@
 package search
 
+import "github.com/ishtaka/go-game-programming/chapter04/math"
+
 type WeightedEdge struct {
 	// Which nodes are connected by this edge?
 	from *WeightedGraphNode
@@ -18,6 +20,8 @@ func NewWeightedEdge(from, to *WeightedGraphNode, weight float32) *WeightedEdge
 
 type WeightedGraphNode struct {
 	edges []*WeightedEdge
+	// For heuristics
+	position math.Vector2
 }
 
 func NewWeightedGraphNode(size int) *WeightedGraphNode {
@@ -26,13 +30,23 @@ func NewWeightedGraphNode(size int) *WeightedGraphNode {
 	}
 }
 
-func (w *WeightedGraphNode) Heuristic(_ *WeightedGraphNode) float32 {
-	return 0.0
+func (w *WeightedGraphNode) GetPosition() math.Vector2 {
+	return w.position
+}
+
+func (w *WeightedGraphNode) SetPosition(pos math.Vector2) {
+	w.position = pos
+}
 
+// AddEdge adds an edge from this node to another.
+func (w *WeightedGraphNode) AddEdge(to *WeightedGraphNode, weight float32) {
+	w.edges = append(w.edges, NewWeightedEdge(w, to, weight))
 }
 
 type WeightedGraph struct {
 	nodes []*WeightedGraphNode
+	// Estimates the cost between node positions
+	heuristic HeuristicFunc
 }
 
 func NewWeightedGraph(size int) *WeightedGraph {
@@ -40,3 +54,13 @@ func NewWeightedGraph(size int) *WeightedGraph {
 		nodes: make([]*WeightedGraphNode, 0, size),
 	}
 }
+
+func (w *WeightedGraph) AddNode(node *WeightedGraphNode) {
+	w.nodes = append(w.nodes, node)
+}
+
+// SetHeuristic sets the heuristic for A* and GBFS. For A* to find the
+// cheapest path, it must never be more than the cost between nodes.
+func (w *WeightedGraph) SetHeuristic(h HeuristicFunc) {
+	w.heuristic = h
+}
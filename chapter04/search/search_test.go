@@ -1,17 +1,26 @@
 package search
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/ishtaka/go-game-programming/chapter04/math"
+)
 
 func createWeightedGraph(t *testing.T) *WeightedGraph {
 	t.Helper()
 
-	l := 5
+	return newGridGraph(5)
+}
+
+// newGridGraph makes an l x l grid, with each node connected to the 4 around it.
+func newGridGraph(l int) *WeightedGraph {
 	g := NewWeightedGraph(l * l)
 
 	for i := 0; i < l; i++ {
 		for j := 0; j < l; j++ {
 			node := NewWeightedGraphNode(4)
-			g.nodes = append(g.nodes, node)
+			node.SetPosition(math.Vector2{X: float32(j), Y: float32(i)})
+			g.AddNode(node)
 		}
 	}
 
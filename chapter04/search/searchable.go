@@ -0,0 +1,114 @@
+package search
+
+// Searchable is any graph that can be searched, with nodes of type N.
+type Searchable[N comparable] interface {
+	// Neighbors returns the nodes one edge away from n
+	Neighbors(n N) []N
+	// Cost returns the cost of the edge from a node to one of its neighbors
+	Cost(from, to N) float32
+	// Heuristic estimates the cost from n to goal. A* only finds the
+	// cheapest path if this never overestimates.
+	Heuristic(n, goal N) float32
+}
+
+type pathScratch[N comparable] struct {
+	parent          N
+	heuristic       float32
+	actualFromStart float32
+	inOpenSet       bool
+	inClosedSet     bool
+}
+
+// FindPath uses A* to find the cheapest path from start to goal.
+func FindPath[N comparable](g Searchable[N], start, goal N) (Path[N], bool) {
+	scratch := make(map[N]*pathScratch[N])
+	get := func(n N) *pathScratch[N] {
+		data, ok := scratch[n]
+		if !ok {
+			data = &pathScratch[N]{}
+			scratch[n] = data
+		}
+		return data
+	}
+
+	openSet := NewPriorityQueue[N](0)
+
+	// Set current node to start, and mark in closed set
+	current := start
+	get(current).inClosedSet = true
+
+	for current != goal {
+		// Add adjacent nodes to open set
+		for _, neighbor := range g.Neighbors(current) {
+			data := get(neighbor)
+			// Only check nodes that aren't in the closed set
+			if data.inClosedSet {
+				continue
+			}
+
+			newG := scratch[current].actualFromStart + g.Cost(current, neighbor)
+			if !data.inOpenSet {
+				// Not in the open set, so parent must be current
+				data.parent = current
+				data.heuristic = g.Heuristic(neighbor, goal)
+				data.actualFromStart = newG
+				data.inOpenSet = true
+				openSet.Push(neighbor, data.heuristic+data.actualFromStart)
+			} else if newG < data.actualFromStart {
+				// Current should adopt this node
+				data.parent = current
+				data.actualFromStart = newG
+				openSet.Push(neighbor, data.heuristic+data.actualFromStart)
+			}
+		}
+
+		// If open set is empty, all possible paths are exhausted
+		if openSet.IsEmpty() {
+			return Path[N]{}, false
+		}
+
+		// Move the lowest cost node from open to closed, and set to current
+		current = openSet.Pop()
+		scratch[current].inOpenSet = false
+		scratch[current].inClosedSet = true
+	}
+
+	return buildPath(start, goal, func(n N) (N, float32) {
+		p := scratch[n].parent
+		return p, g.Cost(p, n)
+	}), true
+}
+
+// Neighbors returns the nodes n has edges to.
+func (w *WeightedGraph) Neighbors(n *WeightedGraphNode) []*WeightedGraphNode {
+	neighbors := make([]*WeightedGraphNode, len(n.edges))
+	for i, edge := range n.edges {
+		neighbors[i] = edge.to
+	}
+
+	return neighbors
+}
+
+// Cost returns the weight of the lightest edge from one node to another.
+func (w *WeightedGraph) Cost(from, to *WeightedGraphNode) float32 {
+	var cost float32
+	found := false
+	for _, edge := range from.edges {
+		if edge.to == to && (!found || edge.weight < cost) {
+			cost = edge.weight
+			found = true
+		}
+	}
+
+	return cost
+}
+
+// Heuristic estimates the cost between nodes with the graph's heuristic,
+// or returns 0 if it doesn't have one.
+func (w *WeightedGraph) Heuristic(n, goal *WeightedGraphNode) float32 {
+	if w.heuristic == nil {
+		return 0
+	}
+
+	return w.heuristic(n.position, goal.position)
+}
@@ -0,0 +1,34 @@
+package search
+
+import "testing"
+
+func TestFindPath(t *testing.T) {
+	g := createWeightedGraph(t)
+
+	path, ok := FindPath[*WeightedGraphNode](g, g.nodes[0], g.nodes[9])
+	if !ok {
+		t.Fatal("path not found")
+	}
+	// 1 down and 4 across
+	if len(path.Nodes) != 6 || len(path.Edges) != 5 || path.Cost != 5 {
+		t.Fatalf("expected 6 nodes, 5 edges and cost 5, got %d, %d and %f", len(path.Nodes), len(path.Edges), path.Cost)
+	}
+	if path.Nodes[0] != g.nodes[0] || path.Nodes[5] != g.nodes[9] {
+		t.Error("expected path from start to goal")
+	}
+	for i, edge := range path.Edges {
+		if edge.From != path.Nodes[i] || edge.To != path.Nodes[i+1] {
+			t.Errorf("edge %d doesn't join nodes %d and %d", i, i, i+1)
+		}
+	}
+
+	if path, ok := FindPath[*WeightedGraphNode](g, g.nodes[3], g.nodes[3]); !ok || len(path.Nodes) != 1 || path.Cost != 0 {
+		t.Errorf("expected path to self to be 1 node, got %d", len(path.Nodes))
+	}
+
+	// A node with no edges can't be reached
+	island := NewWeightedGraphNode(0)
+	if _, ok := FindPath[*WeightedGraphNode](g, g.nodes[0], island); ok {
+		t.Error("expected no path")
+	}
+}
@@ -0,0 +1,95 @@
+package search
+
+import "github.com/ishtaka/go-game-programming/chapter04/math"
+
+// Cell is a column/row on a UniformGrid.
+type Cell struct {
+	X, Y int
+}
+
+// UniformGrid is a grid where every open cell costs the same to enter.
+// Moves go to the 8 cells around, but never cut the corner of a blocked cell.
+type UniformGrid struct {
+	width, height int
+	blocked       []bool
+}
+
+func NewUniformGrid(width, height int) *UniformGrid {
+	return &UniformGrid{
+		width:   width,
+		height:  height,
+		blocked: make([]bool, width*height),
+	}
+}
+
+func (u *UniformGrid) GetWidth() int {
+	return u.width
+}
+
+func (u *UniformGrid) GetHeight() int {
+	return u.height
+}
+
+// SetBlocked blocks or unblocks a cell on the grid.
+func (u *UniformGrid) SetBlocked(c Cell, blocked bool) {
+	if u.contains(c) {
+		u.blocked[c.Y*u.width+c.X] = blocked
+	}
+}
+
+// IsWalkable returns whether c is on the grid and not blocked.
+func (u *UniformGrid) IsWalkable(c Cell) bool {
+	return u.contains(c) && !u.blocked[c.Y*u.width+c.X]
+}
+
+func (u *UniformGrid) contains(c Cell) bool {
+	return c.X >= 0 && c.X < u.width && c.Y >= 0 && c.Y < u.height
+}
+
+// Neighbors returns the open cells around c, leaving out diagonals that
+// would cut a corner. A blocked cell has none.
+func (u *UniformGrid) Neighbors(c Cell) []Cell {
+	if !u.IsWalkable(c) {
+		return nil
+	}
+
+	neighbors := make([]Cell, 0, 8)
+	for dy := -1; dy <= 1; dy++ {
+		for dx := -1; dx <= 1; dx++ {
+			if (dx != 0 || dy != 0) && u.canStep(c, dx, dy) {
+				neighbors = append(neighbors, Cell{c.X + dx, c.Y + dy})
+			}
+		}
+	}
+
+	return neighbors
+}
+
+// canStep returns whether one step from c in direction dx/dy is allowed.
+func (u *UniformGrid) canStep(c Cell, dx, dy int) bool {
+	if !u.IsWalkable(Cell{c.X + dx, c.Y + dy}) {
+		return false
+	}
+	if dx != 0 && dy != 0 {
+		return u.IsWalkable(Cell{c.X + dx, c.Y}) && u.IsWalkable(Cell{c.X, c.Y + dy})
+	}
+
+	return true
+}
+
+// Cost returns 1 for a straight step, or the square root of 2 for a diagonal.
+func (u *UniformGrid) Cost(from, to Cell) float32 {
+	if from.X != to.X && from.Y != to.Y {
+		return math.Sqrt(2)
+	}
+
+	return 1
+}
+
+func (u *UniformGrid) Heuristic(c, goal Cell) float32 {
+	return Octile(c.toVector2(), goal.toVector2())
+}
+
+func (c Cell) toVector2() math.Vector2 {
+	return math.Vector2{X: float32(c.X), Y: float32(c.Y)}
+}
@@ -0,0 +1,104 @@
+package search
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/ishtaka/go-game-programming/chapter04/math"
+)
+
+// newRandomGrid blocks about a quarter of the cells, except the corners.
+func newRandomGrid(l int, seed int64) *UniformGrid {
+	g := NewUniformGrid(l, l)
+	r := rand.New(rand.NewSource(seed))
+	for y := 0; y < l; y++ {
+		for x := 0; x < l; x++ {
+			g.SetBlocked(Cell{x, y}, r.Intn(4) == 0)
+		}
+	}
+	g.SetBlocked(Cell{0, 0}, false)
+	g.SetBlocked(Cell{l - 1, l - 1}, false)
+
+	return g
+}
+
+// checkPath fails if path doesn't go from start to goal in single steps.
+func checkPath(t *testing.T, name string, g *UniformGrid, path Path[Cell], start, goal Cell) {
+	t.Helper()
+
+	if path.Nodes[0] != start || path.Nodes[len(path.Nodes)-1] != goal {
+		t.Errorf("%s: expected path from %v to %v", name, start, goal)
+	}
+	for _, edge := range path.Edges {
+		d := Cell{edge.To.X - edge.From.X, edge.To.Y - edge.From.Y}
+		if max(abs(d.X), abs(d.Y)) != 1 || !g.canStep(edge.From, d.X, d.Y) {
+			t.Errorf("%s: can't step from %v to %v", name, edge.From, edge.To)
+		}
+	}
+}
+
+func abs(x int) int {
+	return x * sign(x)
+}
+
+func TestSearchesAgree(t *testing.T) {
+	finders := map[string]func(g *UniformGrid, start, goal Cell) (Path[Cell], bool){
+		"astar": func(g *UniformGrid, start, goal Cell) (Path[Cell], bool) {
+			return FindPath[Cell](g, start, goal)
+		},
+		"dijkstra": func(g *UniformGrid, start, goal Cell) (Path[Cell], bool) {
+			return Dijkstra[Cell](g, start, goal)
+		},
+		"bidirectional": func(g *UniformGrid, start, goal Cell) (Path[Cell], bool) {
+			return BidirectionalAStar[Cell](g, start, goal)
+		},
+		"jps": JumpPointSearch,
+	}
+
+	for seed := range int64(20) {
+		g := newRandomGrid(24, seed)
+		start, goal := Cell{0, 0}, Cell{23, 23}
+		want, found := DistancesFrom[Cell](g, start).GetDistance(goal)
+
+		for name, find := range finders {
+			path, ok := find(g, start, goal)
+			if ok != found {
+				t.Errorf("seed %d %s: expected found %v, got %v", seed, name, found, ok)
+				continue
+			}
+			if !ok {
+				continue
+			}
+			if !math.NearZero(path.Cost - want) {
+				t.Errorf("seed %d %s: expected cost %f, got %f", seed, name, want, path.Cost)
+			}
+			checkPath(t, name, g, path, start, goal)
+		}
+	}
+}
+
+func TestJumpPointSearch(t *testing.T) {
+	// A wall down the middle with a gap at the bottom
+	g := NewUniformGrid(5, 5)
+	for y := 0; y < 4; y++ {
+		g.SetBlocked(Cell{2, y}, true)
+	}
+
+	path, ok := JumpPointSearch(g, Cell{0, 0}, Cell{4, 0})
+	if !ok {
+		t.Fatal("path not found")
+	}
+	checkPath(t, "jps", g, path, Cell{0, 0}, Cell{4, 0})
+	// Down 3, through the gap and back up, without cutting the wall's corners
+	if want := 4 + 2*math.Sqrt(2) + 4; !math.NearZero(path.Cost - want) {
+		t.Errorf("expected cost %f, got %f", want, path.Cost)
+	}
+
+	g.SetBlocked(Cell{2, 4}, true)
+	if _, ok := JumpPointSearch(g, Cell{0, 0}, Cell{4, 0}); ok {
+		t.Error("expected no path through the wall")
+	}
+	if _, ok := JumpPointSearch(g, Cell{0, 0}, Cell{2, 0}); ok {
+		t.Error("expected no path to a blocked cell")
+	}
+}
@@ -6,7 +6,7 @@ const DefaultDrawOrder = 100
 
 type Sprite interface {
 	Component
-	Draw(renderer *sdl.Renderer)
+	Draw(renderer *sdl.Renderer, alpha float32)
 	GetDrawOrder() int
 	SetTexture(tex *sdl.Texture)
 	GetTexWidth() int32
@@ -31,7 +31,7 @@ func NewSpriteComponent(owner Actor, drawOrder int) *SpriteComponent {
 	return sc
 }
 
-func (s *SpriteComponent) Draw(renderer *sdl.Renderer) {
+func (s *SpriteComponent) Draw(renderer *sdl.Renderer, alpha float32) {
 	if s.tex != nil {
 		r := sdl.Rect{}
 		owner := s.GetOwner()
@@ -41,12 +41,13 @@ func (s *SpriteComponent) Draw(renderer *sdl.Renderer) {
 		r.H = int32(float32(s.texHeight) * owner.GetScale())
 
 		// Center the rectangle around the position of the owner
-		pos := owner.GetPosition()
+		// (interpolated between the last two updates)
+		pos := owner.GetInterpolatedPosition(alpha)
 		r.X = int32(pos.X - float32(r.W)/2)
 		r.Y = int32(pos.Y - float32(r.H)/2)
 
 		// Draw (have to convert angle from radians to degrees, and clockwise to counter)
-		if err := renderer.CopyEx(s.tex, nil, &r, -owner.GetRotation().Degrees(), nil, sdl.FLIP_NONE); err != nil {
+		if err := renderer.CopyEx(s.tex, nil, &r, -owner.GetInterpolatedRotation(alpha).Degrees(), nil, sdl.FLIP_NONE); err != nil {
 			sdl.Log("failed to copy texture: %s\n", err)
 		}
 	}
@@ -54,6 +55,12 @@ func (s *SpriteComponent) Draw(renderer *sdl.Renderer) {
 
 func (s *SpriteComponent) SetTexture(tex *sdl.Texture) {
 	s.tex = tex
+	if tex == nil {
+		s.texWidth = 0
+		s.texHeight = 0
+		return
+	}
+
 	_, _, width, height, _ := tex.Query()
 	// Set width/height
 	s.texWidth = width
@@ -71,9 +78,3 @@ func (s *SpriteComponent) GetTexWidth() int32 {
 func (s *SpriteComponent) GetTexHeight() int32 {
 	return s.texHeight
 }
-
-func (s *SpriteComponent) Destroy() {
-	owner := s.GetOwner()
-	owner.RemoveComponent(s)
-	owner.GetGame().RemoveSprite(s)
-}
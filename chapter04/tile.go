@@ -21,26 +21,28 @@ func (t TileState) String() string {
 type Tile struct {
 	Actor
 	// For pathfinding
-	adjacent               []*Tile
-	parent                 *Tile
-	f, g, h                float32
-	inOpenSet, inClosedSet bool
-	blocked                bool
+	adjacent []*Tile
+	blocked  bool
+	// Cost of moving onto this tile, in tiles
+	cost    float32
+	terrain string
 
 	sprite   Sprite
 	state    TileState
 	selected bool
+	// Texture for the default state, instead of the usual one
+	texture string
 }
 
 func NewTile(game *Game) *Tile {
 	t := &Tile{
 		Actor: NewActor(game),
+		cost:  1,
 	}
 
 	t.sprite = NewSpriteComponent(t, DefaultDrawOrder)
 	t.updateTexture()
 	t.AddComponent(t.sprite)
-	game.AddSprite(t.sprite)
 
 	game.AddActor(t)
 
@@ -61,8 +63,27 @@ func (t *Tile) ToggleSelect() {
 	t.updateTexture()
 }
 
-func (t *Tile) GetParent() *Tile {
-	return t.parent
+// GetCost returns the cost of moving onto this tile, in tiles.
+func (t *Tile) GetCost() float32 {
+	return t.cost
+}
+
+func (t *Tile) SetCost(cost float32) {
+	t.cost = cost
+}
+
+func (t *Tile) GetTerrain() string {
+	return t.terrain
+}
+
+func (t *Tile) SetTerrain(terrain string) {
+	t.terrain = terrain
+}
+
+// SetTexture sets the texture for the default state, or the usual one if empty.
+func (t *Tile) SetTexture(texture string) {
+	t.texture = texture
+	t.updateTexture()
 }
 
 func (t *Tile) updateTexture() {
@@ -83,6 +104,8 @@ func (t *Tile) updateTexture() {
 	default:
 		if t.selected {
 			text = "Assets/TileBrownSelected.png"
+		} else if t.texture != "" {
+			text = t.texture
 		} else {
 			text = "Assets/TileBrown.png"
 		}
@@ -90,8 +113,3 @@ func (t *Tile) updateTexture() {
 
 	t.sprite.SetTexture(t.GetGame().GetTexture(text))
 }
-
-func (t *Tile) Destroy() {
-	t.GetGame().RemoveActor(t)
-	t.DestroyComponents()
-}
@@ -21,7 +21,6 @@ func NewTower(game *Game) *Tower {
 
 	sc := NewSpriteComponent(t, DefaultDrawOrder)
 	sc.SetTexture(game.GetTexture("Assets/Tower.png"))
-	game.AddSprite(sc)
 	t.AddComponent(sc)
 
 	t.move = NewMoveComponent(t, DefaultUpdateOrder)
@@ -63,8 +62,3 @@ func (t *Tower) UpdateActor(deltaTime float32) {
 		t.nextAttack = t.attackTime
 	}
 }
-
-func (t *Tower) Destroy() {
-	t.GetGame().RemoveActor(t)
-	t.DestroyComponents()
-}
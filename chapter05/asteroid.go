@@ -7,7 +7,6 @@ import (
 
 type Asteroid struct {
 	Actor
-	circle CircleComponent
 }
 
 func NewAsteroid(game *Game, drawOrder int) *Asteroid {
@@ -15,16 +14,18 @@ func NewAsteroid(game *Game, drawOrder int) *Asteroid {
 		Actor: NewActor(game),
 	}
 
-	randPos := rand.GetVector2(math.ZeroVector2, math.Vector2{X: -512, Y: -384})
+	// Spawns draw from their own stream, so they don't depend on other randomness
+	spawn := game.GetRNG().GetStream(rand.StreamSpawn)
+
+	randPos := spawn.GetVector2(math.ZeroVector2, math.Vector2{X: -512, Y: -384})
 	s.SetPosition(randPos)
 
-	randAngle := math.Angle(rand.GetFloatRange(0, math.TwoPi))
+	randAngle := math.Angle(spawn.GetFloatRange(0, math.TwoPi))
 	s.SetRotation(randAngle)
 
 	// create a sprite component
 	sc := NewSpriteComponent(s, drawOrder)
 	sc.SetTexture(game.GetTexture("Assets/Asteroid.png"))
-	game.AddSprite(sc)
 	s.AddComponent(sc)
 
 	// create a move component
@@ -33,22 +34,21 @@ func NewAsteroid(game *Game, drawOrder int) *Asteroid {
 	s.AddComponent(mc)
 
 	// create a circle component
-	s.circle = NewCircleComponent(s, DefaultUpdateOrder)
-	s.circle.SetRadius(40)
-	s.AddComponent(s.circle)
+	cc := NewCircleComponent(s, DefaultUpdateOrder)
+	cc.SetRadius(40)
+	cc.SetLayer(LayerAsteroid)
+	cc.SetMask(LayerLaser)
+	s.AddComponent(cc)
 
 	game.AddActor(s)
-	game.AddAsteroid(s)
 
 	return s
 }
 
-func (a *Asteroid) GetCircle() CircleComponent {
-	return a.circle
+func (a *Asteroid) OnAdded() {
+	a.GetGame().AddAsteroid(a)
 }
 
-func (a *Asteroid) Destroy() {
-	a.GetGame().RemoveActor(a)
+func (a *Asteroid) OnRemoved() {
 	a.GetGame().RemoveAsteroid(a)
-	a.DestroyComponents()
 }
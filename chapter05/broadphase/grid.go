@@ -0,0 +1,358 @@
+package broadphase
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/ishtaka/go-game-programming/chapter05/math"
+)
+
+// Grid is a uniform grid of square cells for finding circles near a point
+// or near each other, without testing every pair. Each circle is kept in
+// every cell its bounding box touches, so the cell size should be around
+// the size of the largest circle.
+//
+// Results are in the order items were inserted, so they don't depend on map order.
+type Grid[T comparable] struct {
+	cellSize float32
+	cells    map[cell][]*entry[T]
+	entries  map[T]*entry[T]
+	nextID   int
+	// Occupied cells in each column and row, and the first and last of them
+	colCount, rowCount map[int]int
+	lo, hi             cell
+}
+
+// Pair is two items whose circles intersect.
+type Pair[T comparable] struct {
+	A, B T
+}
+
+type cell struct {
+	x, y int
+}
+
+type entry[T comparable] struct {
+	item T
+	// Insertion order
+	id       int
+	center   math.Vector2
+	radius   float32
+	min, max cell
+}
+
+func NewGrid[T comparable](cellSize float32) *Grid[T] {
+	return &Grid[T]{
+		cellSize: cellSize,
+		cells:    make(map[cell][]*entry[T]),
+		entries:  make(map[T]*entry[T]),
+		colCount: make(map[int]int),
+		rowCount: make(map[int]int),
+	}
+}
+
+func (g *Grid[T]) GetCellSize() float32 {
+	return g.cellSize
+}
+
+// Len returns the number of items in the grid.
+func (g *Grid[T]) Len() int {
+	return len(g.entries)
+}
+
+// Insert adds an item with a circle, or moves it if it's already in the grid.
+func (g *Grid[T]) Insert(item T, center math.Vector2, radius float32) {
+	if _, ok := g.entries[item]; ok {
+		g.Update(item, center, radius)
+		return
+	}
+
+	e := &entry[T]{item: item, id: g.nextID}
+	g.nextID++
+	g.entries[item] = e
+	g.place(e, center, radius)
+}
+
+// Update moves an item's circle. Items not in the grid are inserted.
+func (g *Grid[T]) Update(item T, center math.Vector2, radius float32) {
+	e, ok := g.entries[item]
+	if !ok {
+		g.Insert(item, center, radius)
+		return
+	}
+
+	// Only move between cells if the circle left the ones it was in
+	lo, hi := g.bounds(center, radius)
+	if lo == e.min && hi == e.max {
+		e.center = center
+		e.radius = radius
+		return
+	}
+
+	g.unplace(e)
+	g.place(e, center, radius)
+}
+
+// Remove takes an item out of the grid.
+func (g *Grid[T]) Remove(item T) {
+	e, ok := g.entries[item]
+	if !ok {
+		return
+	}
+
+	g.unplace(e)
+	delete(g.entries, item)
+}
+
+// QueryRadius returns the items whose circles intersect the circle at center.
+func (g *Grid[T]) QueryRadius(center math.Vector2, radius float32) []T {
+	lo, hi := g.bounds(center, radius)
+
+	var found []*entry[T]
+	seen := make(map[*entry[T]]bool)
+	for x := lo.x; x <= hi.x; x++ {
+		for y := lo.y; y <= hi.y; y++ {
+			for _, e := range g.cells[cell{x, y}] {
+				if !seen[e] && intersect(e.center, e.radius, center, radius) {
+					found = append(found, e)
+				}
+				seen[e] = true
+			}
+		}
+	}
+
+	return items(found)
+}
+
+// Nearest returns the item whose center is nearest to pos, out of the items
+// accepted by filter (or all items, if filter is nil).
+func (g *Grid[T]) Nearest(pos math.Vector2, filter func(T) bool) (T, bool) {
+	var best *entry[T]
+	var bestDistSq float32
+
+	if len(g.cells) == 0 {
+		var zero T
+		return zero, false
+	}
+
+	// Rings of cells nearer or further out than any item don't need checking
+	c := g.cellOf(pos)
+	minRing := max(0, g.lo.x-c.x, c.x-g.hi.x, g.lo.y-c.y, c.y-g.hi.y)
+	maxRing := max(c.x-g.lo.x, g.hi.x-c.x, c.y-g.lo.y, g.hi.y-c.y)
+
+	seen := make(map[*entry[T]]bool)
+	check := func(e *entry[T]) {
+		if seen[e] {
+			return
+		}
+		seen[e] = true
+
+		if filter != nil && !filter(e.item) {
+			return
+		}
+		distSq := e.center.Sub(pos).LengthSq()
+		if best == nil || distSq < bestDistSq || (distSq == bestDistSq && e.id < best.id) {
+			best = e
+			bestDistSq = distSq
+		}
+	}
+
+	for ring := minRing; ring <= maxRing && len(seen) < len(g.entries); ring++ {
+		// Anything not seen yet is in a further ring, so at least this far away
+		if best != nil {
+			minDist := float32(ring-1) * g.cellSize
+			if minDist > 0 && bestDistSq < minDist*minDist {
+				break
+			}
+		}
+
+		for _, k := range g.ringCells(c, ring) {
+			for _, e := range g.cells[k] {
+				check(e)
+			}
+		}
+	}
+
+	if best == nil {
+		var zero T
+		return zero, false
+	}
+
+	return best.item, true
+}
+
+// Pairs returns every pair of items whose circles intersect.
+func (g *Grid[T]) Pairs() []Pair[T] {
+	type key struct {
+		a, b *entry[T]
+	}
+	seen := make(map[key]bool)
+
+	var pairs []key
+	for _, cellEntries := range g.cells {
+		for i, a := range cellEntries {
+			for _, b := range cellEntries[i+1:] {
+				if a.id > b.id {
+					a, b = b, a
+				}
+				k := key{a, b}
+				if seen[k] {
+					continue
+				}
+				seen[k] = true
+
+				if intersect(a.center, a.radius, b.center, b.radius) {
+					pairs = append(pairs, k)
+				}
+			}
+		}
+	}
+
+	slices.SortFunc(pairs, func(p, q key) int {
+		if c := cmp.Compare(p.a.id, q.a.id); c != 0 {
+			return c
+		}
+		return cmp.Compare(p.b.id, q.b.id)
+	})
+
+	result := make([]Pair[T], len(pairs))
+	for i, p := range pairs {
+		result[i] = Pair[T]{A: p.a.item, B: p.b.item}
+	}
+
+	return result
+}
+
+func (g *Grid[T]) cellOf(p math.Vector2) cell {
+	return cell{
+		x: int(math.Floor(p.X / g.cellSize)),
+		y: int(math.Floor(p.Y / g.cellSize)),
+	}
+}
+
+// bounds returns the first and last cells touched by a circle's bounding box
+func (g *Grid[T]) bounds(center math.Vector2, radius float32) (lo, hi cell) {
+	r := math.Vector2{X: radius, Y: radius}
+	return g.cellOf(center.Sub(r)), g.cellOf(center.Add(r))
+}
+
+// place adds e to the cells its circle touches
+func (g *Grid[T]) place(e *entry[T], center math.Vector2, radius float32) {
+	e.center = center
+	e.radius = radius
+	e.min, e.max = g.bounds(center, radius)
+
+	for x := e.min.x; x <= e.max.x; x++ {
+		for y := e.min.y; y <= e.max.y; y++ {
+			c := cell{x, y}
+			if len(g.cells[c]) == 0 {
+				g.occupy(c)
+			}
+			g.cells[c] = append(g.cells[c], e)
+		}
+	}
+}
+
+// unplace removes e from the cells it's in, and drops cells left empty
+func (g *Grid[T]) unplace(e *entry[T]) {
+	for x := e.min.x; x <= e.max.x; x++ {
+		for y := e.min.y; y <= e.max.y; y++ {
+			c := cell{x, y}
+			g.cells[c] = slices.DeleteFunc(g.cells[c], func(other *entry[T]) bool {
+				return other == e
+			})
+			if len(g.cells[c]) == 0 {
+				delete(g.cells, c)
+				g.vacate(c)
+			}
+		}
+	}
+}
+
+// occupy counts c as occupied, and grows the bounds to take it in
+func (g *Grid[T]) occupy(c cell) {
+	if len(g.colCount) == 0 {
+		g.lo, g.hi = c, c
+	} else {
+		g.lo = cell{min(g.lo.x, c.x), min(g.lo.y, c.y)}
+		g.hi = cell{max(g.hi.x, c.x), max(g.hi.y, c.y)}
+	}
+	g.colCount[c.x]++
+	g.rowCount[c.y]++
+}
+
+// vacate counts c as empty, and shrinks the bounds past any empty columns
+// and rows left at the edges
+func (g *Grid[T]) vacate(c cell) {
+	if g.colCount[c.x]--; g.colCount[c.x] == 0 {
+		delete(g.colCount, c.x)
+	}
+	if g.rowCount[c.y]--; g.rowCount[c.y] == 0 {
+		delete(g.rowCount, c.y)
+	}
+	if len(g.colCount) == 0 {
+		return
+	}
+
+	for g.colCount[g.lo.x] == 0 {
+		g.lo.x++
+	}
+	for g.colCount[g.hi.x] == 0 {
+		g.hi.x--
+	}
+	for g.rowCount[g.lo.y] == 0 {
+		g.lo.y++
+	}
+	for g.rowCount[g.hi.y] == 0 {
+		g.hi.y--
+	}
+}
+
+func intersect(c1 math.Vector2, r1 float32, c2 math.Vector2, r2 float32) bool {
+	radii := r1 + r2
+	return c1.Sub(c2).LengthSq() <= radii*radii
+}
+
+// items returns the items of entries in insertion order
+func items[T comparable](entries []*entry[T]) []T {
+	slices.SortFunc(entries, func(a, b *entry[T]) int {
+		return cmp.Compare(a.id, b.id)
+	})
+
+	result := make([]T, len(entries))
+	for i, e := range entries {
+		result[i] = e.item
+	}
+
+	return result
+}
+
+// ringCells returns the cells at exactly ring cells away from c,
+// leaving out any outside the occupied bounds
+func (g *Grid[T]) ringCells(c cell, ring int) []cell {
+	if ring == 0 {
+		return []cell{c}
+	}
+
+	var cells []cell
+	loX, hiX := max(c.x-ring, g.lo.x), min(c.x+ring, g.hi.x)
+	loY, hiY := max(c.y-ring+1, g.lo.y), min(c.y+ring-1, g.hi.y)
+	for x := loX; x <= hiX; x++ {
+		if c.y-ring >= g.lo.y {
+			cells = append(cells, cell{x, c.y - ring})
+		}
+		if c.y+ring <= g.hi.y {
+			cells = append(cells, cell{x, c.y + ring})
+		}
+	}
+	for y := loY; y <= hiY; y++ {
+		if c.x-ring >= g.lo.x {
+			cells = append(cells, cell{c.x - ring, y})
+		}
+		if c.x+ring <= g.hi.x {
+			cells = append(cells, cell{c.x + ring, y})
+		}
+	}
+
+	return cells
+}
@@ -3,18 +3,43 @@ package chapter05
 import (
 	"fmt"
 	"slices"
+	"time"
 
 	"github.com/go-gl/gl/v3.3-core/gl"
 	"github.com/veandco/go-sdl2/sdl"
 
+	"github.com/ishtaka/go-game-programming/chapter05/broadphase"
 	"github.com/ishtaka/go-game-programming/chapter05/math"
+	"github.com/ishtaka/go-game-programming/chapter05/math/rand"
 )
 
+// DefaultTickRate is the number of simulation steps per second.
+const DefaultTickRate = 60
+
+// collisionCellSize is the size of the grid cells circles are sorted into.
+const collisionCellSize = 128
+
+// maxFrameTime is the most time simulated in a single frame (in seconds).
+const maxFrameTime = 0.25
+
 type Game struct {
-	window     *sdl.Window
-	glContext  sdl.GLContext
-	ticksCount uint64
-	isRunning  bool
+	window      *sdl.Window
+	glContext   sdl.GLContext
+	inputSystem *InputSystem
+	inputMap    *InputMap
+	rng         *rand.RNG
+	clock       Clock
+	lastTime    time.Duration
+	isRunning   bool
+	// Run the simulation without a window or renderer
+	headless bool
+
+	// Time between simulation steps (in seconds)
+	fixedDeltaTime float32
+	// Time not yet simulated
+	accumulator float32
+	// Fraction of a step to interpolate drawing by
+	alpha float32
 
 	// Map of textures loaded
 	textures map[string]*Texture
@@ -26,6 +51,10 @@ type Game struct {
 	// Track if we're updating actors right now
 	updatingActors bool
 
+	// Circle components sorted by position, for collision queries
+	collisions      *broadphase.Grid[CircleComponent]
+	collisionSystem *CollisionSystem
+
 	// All the sprite components drawn
 	sprites []Sprite
 	// Sprite shader
@@ -37,15 +66,35 @@ type Game struct {
 	asteroids []*Asteroid
 }
 
-func NewGame() *Game {
+func NewGame(clock Clock) *Game {
 	return &Game{
-		ticksCount: 0,
-		textures:   make(map[string]*Texture),
-		isRunning:  true,
+		clock:           clock,
+		rng:             rand.NewRNG(uint64(time.Now().UnixNano())),
+		fixedDeltaTime:  1.0 / DefaultTickRate,
+		collisions:      broadphase.NewGrid[CircleComponent](collisionCellSize),
+		collisionSystem: NewCollisionSystem(),
+		textures:        make(map[string]*Texture),
+		isRunning:       true,
 	}
 }
 
+// NewHeadlessGame creates a game that simulates without a window or OpenGL context.
+// Use Step to advance the simulation.
+func NewHeadlessGame(clock Clock) *Game {
+	g := NewGame(clock)
+	g.headless = true
+
+	return g
+}
+
 func (g *Game) Initialize() error {
+	if g.headless {
+		g.loadData()
+		g.savePreviousTransforms()
+		g.lastTime = g.clock.Now()
+		return nil
+	}
+
 	if err := sdl.Init(sdl.INIT_EVERYTHING); err != nil {
 		sdl.Log("unable to initialize SDL: %s\n", err)
 		return err
@@ -88,6 +137,9 @@ func (g *Game) Initialize() error {
 		return err
 	}
 
+	// Wait for vertical sync when swapping buffers
+	_ = sdl.GLSetSwapInterval(1)
+
 	// On some platforms, GLEW will emit a benign error code,
 	// so clear it
 	gl.GetError()
@@ -100,68 +152,131 @@ func (g *Game) Initialize() error {
 	// Create quad for drawing sprites
 	g.createSpriteVerts()
 
+	// Initialize input system
+	g.inputSystem = NewInputSystem()
+	if err = g.inputSystem.Initialize(); err != nil {
+		sdl.Log("failed to initialize input system: %s\n", err)
+		return err
+	}
+
 	g.loadData()
+	g.savePreviousTransforms()
 
-	g.ticksCount = sdl.GetTicks64()
+	g.lastTime = g.clock.Now()
 
 	return nil
 }
 
 func (g *Game) RunLoop() {
 	for g.isRunning {
+		g.RunFrame()
+	}
+}
+
+// RunFrame runs a single iteration of the game loop, simulating
+// the time passed on the clock since the previous frame.
+func (g *Game) RunFrame() {
+	if !g.headless {
 		g.processInput()
-		g.update()
+	}
+	g.update()
+	if !g.headless {
 		g.generateOutput()
 	}
 }
 
+// Step advances the simulation by deltaTime seconds,
+// without reading input or drawing.
+func (g *Game) Step(deltaTime float32) {
+	g.updateGame(deltaTime)
+}
+
+// SetTickRate sets the number of simulation steps per second.
+func (g *Game) SetTickRate(ticksPerSecond float32) {
+	g.fixedDeltaTime = 1.0 / ticksPerSecond
+}
+
+// GetTickRate returns the number of simulation steps per second.
+func (g *Game) GetTickRate() float32 {
+	return 1.0 / g.fixedDeltaTime
+}
+
 func (g *Game) processInput() {
+	g.inputSystem.PrepareForUpdate()
+
 	for event := sdl.PollEvent(); event != nil; event = sdl.PollEvent() {
 		switch event.(type) {
 		case *sdl.QuitEvent:
 			g.isRunning = false
-			return
+		default:
+			g.inputSystem.ProcessEvent(event)
 		}
+	}
 
-		state := sdl.GetKeyboardState()
-		if state[sdl.SCANCODE_ESCAPE] != 0 {
-			g.isRunning = false
-			return
-		}
+	g.inputSystem.Update()
+	state := g.inputSystem.GetState()
 
-		g.updatingActors = true
-		for _, a := range g.actors {
-			a.ProcessInput(state)
-		}
-		g.updatingActors = false
+	if state.Keyboard.GetKeyValue(sdl.SCANCODE_ESCAPE) {
+		g.isRunning = false
+		return
 	}
+
+	g.updatingActors = true
+	for _, a := range g.actors {
+		a.ProcessInput(state)
+	}
+	g.updatingActors = false
 }
 
 func (g *Game) update() {
-	// SDL_TICKS_PASSED is not available in go-sdl2
-	for {
-		if sdl.GetTicks64() > g.ticksCount+16 {
-			break
-		}
+	// Time elapsed since last frame (converted to seconds)
+	now := g.clock.Now()
+	frameTime := float32((now - g.lastTime).Seconds())
+	g.lastTime = now
+
+	// Clamp the frame time, so a slow frame can't demand more and more
+	// steps to catch up (spiral of death)
+	if frameTime > maxFrameTime {
+		frameTime = maxFrameTime
 	}
 
-	deltaTime := float32(sdl.GetTicks64()-g.ticksCount) / 1000.0
-	if deltaTime > 0.05 {
-		deltaTime = 0.05
+	// Simulate in fixed steps until the accumulated time is used up
+	g.accumulator += frameTime
+	for g.accumulator >= g.fixedDeltaTime {
+		g.updateGame(g.fixedDeltaTime)
+		g.accumulator -= g.fixedDeltaTime
 	}
 
-	g.ticksCount = sdl.GetTicks64()
+	// How far we are between the last step and the next one
+	g.alpha = g.accumulator / g.fixedDeltaTime
+}
+
+func (g *Game) updateGame(deltaTime float32) {
+	// Keep transforms from before this update to interpolate drawing from
+	g.savePreviousTransforms()
 
 	// Update all actors
 	g.updatingActors = true
 	for _, a := range g.actors {
 		a.Update(deltaTime)
 	}
+
+	// Pending actors don't update, so their circles are still wherever
+	// they were when they were added. Move them to where they are now.
+	for _, pending := range g.pendingActors {
+		if cc, ok := GetComponent[CircleComponent](pending); ok {
+			g.collisions.Update(cc, cc.GetCenter(), cc.GetRadius())
+		}
+	}
+
+	// Let actors know about what they've run into
+	g.collisionSystem.Update(g.collisions)
 	g.updatingActors = false
 
 	// Move any pending actors to actors
 	for _, pending := range g.pendingActors {
 		pending.ComputeWorldTransform()
+		pending.SavePreviousTransform()
 		g.actors = append(g.actors, pending)
 	}
 	g.pendingActors = nil
@@ -176,7 +291,16 @@ func (g *Game) update() {
 
 	// Delete dead actors (which removes them from actors)
 	for _, deadActor := range deadActors {
-		deadActor.Destroy()
+		// Children may have already gone with their parent
+		if slices.Contains(g.actors, deadActor) {
+			g.DestroyActor(deadActor)
+		}
+	}
+}
+
+func (g *Game) savePreviousTransforms() {
+	for _, a := range g.actors {
+		a.SavePreviousTransform()
 	}
 }
 
@@ -195,7 +319,7 @@ func (g *Game) generateOutput() {
 	g.spriteVerts.SetActive()
 
 	for _, s := range g.sprites {
-		s.Draw(g.spriteShader)
+		s.Draw(g.spriteShader, g.alpha)
 	}
 
 	// Swap the buffers
@@ -233,6 +357,12 @@ func (g *Game) createSpriteVerts() {
 }
 
 func (g *Game) loadData() {
+	// Use the default controls, unless they've been remapped
+	g.inputMap = NewDefaultInputMap()
+	if !g.headless {
+		g.inputMap.Load("Assets/Controls.json")
+	}
+
 	// Create player's ship
 	g.ship = NewShip(g, DefaultDrawOrder)
 	g.ship.SetPosition(math.Vector2{X: 0, Y: 0})
@@ -248,7 +378,7 @@ func (g *Game) loadData() {
 func (g *Game) unloadData() {
 	// Delete actors
 	for len(g.actors) > 0 {
-		g.actors[0].Destroy()
+		g.DestroyActor(g.actors[0])
 	}
 
 	// Destroy textures
@@ -264,6 +394,11 @@ func (g *Game) GetTexture(fileName string) *Texture {
 		return tex
 	}
 
+	// There is no OpenGL context to create textures with
+	if g.headless {
+		return nil
+	}
+
 	tex := NewTexture()
 	if tex.Load(fileName) {
 		g.textures[fileName] = tex
@@ -275,6 +410,11 @@ func (g *Game) GetTexture(fileName string) *Texture {
 
 func (g *Game) Shutdown() (err error) {
 	defer sdl.Quit()
+	defer func() {
+		if g.inputSystem != nil {
+			g.inputSystem.Shutdown()
+		}
+	}()
 	defer func() {
 		if g.window != nil {
 			err = g.window.Destroy()
@@ -286,10 +426,14 @@ func (g *Game) Shutdown() (err error) {
 		}
 	}()
 	defer func() {
-		g.spriteShader.Unload()
+		if g.spriteShader != nil {
+			g.spriteShader.Unload()
+		}
 	}()
 	defer func() {
-		g.spriteVerts.Destroy()
+		if g.spriteVerts != nil {
+			g.spriteVerts.Destroy()
+		}
 	}()
 
 	g.unloadData()
@@ -297,6 +441,10 @@ func (g *Game) Shutdown() (err error) {
 	return
 }
 
+func (g *Game) GetShip() *Ship {
+	return g.ship
+}
+
 func (g *Game) GetAsteroids() []*Asteroid {
 	return g.asteroids
 }
@@ -311,6 +459,33 @@ func (g *Game) RemoveAsteroid(ast *Asteroid) {
 	})
 }
 
+func (g *Game) GetInputMap() *InputMap {
+	return g.inputMap
+}
+
+// GetRNG returns the game's random number generator.
+// Seed it before Initialize to make the game repeatable.
+func (g *Game) GetRNG() *rand.RNG {
+	return g.rng
+}
+
+func (g *Game) GetClock() Clock {
+	return g.clock
+}
+
+func (g *Game) IsRunning() bool {
+	return g.isRunning
+}
+
+func (g *Game) GetActors() []Actor {
+	return g.actors
+}
+
+// GetCollisionGrid returns the circle components in the game, sorted by position.
+func (g *Game) GetCollisionGrid() *broadphase.Grid[CircleComponent] {
+	return g.collisions
+}
+
 func (g *Game) AddActor(actor Actor) {
 	// If we're updating actors, need to add to pending
 	if g.updatingActors {
@@ -318,9 +493,14 @@ func (g *Game) AddActor(actor Actor) {
 	} else {
 		g.actors = append(g.actors, actor)
 	}
+
+	actor.OnAdded()
 }
 
+// RemoveActor takes the actor out of the game without destroying it.
 func (g *Game) RemoveActor(actor Actor) {
+	count := len(g.pendingActors) + len(g.actors)
+
 	g.pendingActors = slices.DeleteFunc(g.pendingActors, func(a Actor) bool {
 		return a == actor
 	})
@@ -328,6 +508,46 @@ func (g *Game) RemoveActor(actor Actor) {
 	g.actors = slices.DeleteFunc(g.actors, func(a Actor) bool {
 		return a == actor
 	})
+
+	if len(g.pendingActors)+len(g.actors) < count {
+		actor.OnRemoved()
+	}
+}
+
+// DestroyActor removes the actor and all of its components from the game.
+func (g *Game) DestroyActor(actor Actor) {
+	actor.OnDestroy()
+
+	// Detach from the parent, and destroy the children along with the actor
+	if parent := actor.GetParent(); parent != nil {
+		RemoveChild(parent, actor)
+	}
+	for len(actor.GetChildren()) > 0 {
+		g.DestroyActor(actor.GetChildren()[0])
+	}
+
+	// Remove components, highest update order first
+	for components := actor.GetComponents(); len(components) > 0; components = actor.GetComponents() {
+		actor.RemoveComponent(components[len(components)-1])
+	}
+
+	g.RemoveActor(actor)
+}
+
+// addComponent registers a component the game keeps track of, such as a sprite
+func (g *Game) addComponent(c Component) {
+	switch c := c.(type) {
+	case Sprite:
+		g.AddSprite(c)
+	}
+}
+
+// removeComponent unregisters a component added with addComponent
+func (g *Game) removeComponent(c Component) {
+	switch c := c.(type) {
+	case Sprite:
+		g.RemoveSprite(c)
+	}
 }
 
 func (g *Game) AddSprite(s Sprite) {
@@ -2,7 +2,7 @@ package chapter05
 
 type InputComponent interface {
 	Component
-	ProcessInput(keyState []uint8)
+	ProcessInput(state *InputState)
 
 	GetMaxForward() float32
 	SetMaxForwardSpeed(speed float32)
@@ -10,16 +10,11 @@ type InputComponent interface {
 	GetMaxAngular() float32
 	SetMaxAngularSpeed(speed float32)
 
-	GetForwardKey() uint8
-	SetForwardKey(key uint8)
-	GetBackKey() uint8
-	SetBackKey(key uint8)
+	GetForwardAxis() string
+	SetForwardAxis(axis string)
 
-	GetClockwiseKey() uint8
-	SetClockwiseKey(key uint8)
-
-	GetCounterClockwiseKey() uint8
-	SetCounterClockwiseKey(key uint8)
+	GetAngularAxis() string
+	SetAngularAxis(axis string)
 }
 
 type inputComponent struct {
@@ -27,12 +22,9 @@ type inputComponent struct {
 	// The maximum forward/angular speeds
 	maxForwardSpeed float32
 	maxAngularSpeed float32
-	// Keys for forward/back movement
-	forwardKey uint8
-	backKey    uint8
-	// Keys for angular movement
-	clockwiseKey        uint8
-	counterClockwiseKey uint8
+	// Names of the axes in the game's InputMap for forward/angular movement
+	forwardAxis string
+	angularAxis string
 }
 
 func NewInputComponent(owner Actor, updateOrder int) InputComponent {
@@ -44,25 +36,15 @@ func NewInputComponent(owner Actor, updateOrder int) InputComponent {
 	return i
 }
 
-func (i *inputComponent) ProcessInput(keyState []uint8) {
+func (i *inputComponent) ProcessInput(state *InputState) {
+	inputMap := i.GetOwner().GetGame().GetInputMap()
+
 	// Calculate forward speed for MoveComponent
-	forwardSpeed := float32(0.0)
-	if keyState[i.forwardKey] != 0 {
-		forwardSpeed += i.maxForwardSpeed
-	}
-	if keyState[i.backKey] != 0 {
-		forwardSpeed -= i.maxForwardSpeed
-	}
+	forwardSpeed := i.maxForwardSpeed * inputMap.GetAxisValue(state, i.forwardAxis)
 	i.SetForwardSpeed(forwardSpeed)
 
 	// Calculate angular speed for MoveComponent
-	angularSpeed := float32(0.0)
-	if keyState[i.clockwiseKey] != 0 {
-		angularSpeed += i.maxAngularSpeed
-	}
-	if keyState[i.counterClockwiseKey] != 0 {
-		angularSpeed -= i.maxAngularSpeed
-	}
+	angularSpeed := i.maxAngularSpeed * inputMap.GetAxisValue(state, i.angularAxis)
 	i.SetAngularSpeed(angularSpeed)
 }
 
@@ -82,39 +64,18 @@ func (i *inputComponent) SetMaxAngularSpeed(speed float32) {
 	i.maxAngularSpeed = speed
 }
 
-func (i *inputComponent) GetForwardKey() uint8 {
-	return i.forwardKey
-}
-
-func (i *inputComponent) SetForwardKey(key uint8) {
-	i.forwardKey = key
-}
-
-func (i *inputComponent) GetBackKey() uint8 {
-	return i.backKey
-}
-
-func (i *inputComponent) SetBackKey(key uint8) {
-	i.backKey = key
-}
-
-func (i *inputComponent) GetClockwiseKey() uint8 {
-	return i.clockwiseKey
-}
-
-func (i *inputComponent) SetClockwiseKey(key uint8) {
-	i.clockwiseKey = key
+func (i *inputComponent) GetForwardAxis() string {
+	return i.forwardAxis
 }
 
-func (i *inputComponent) GetCounterClockwiseKey() uint8 {
-	return i.counterClockwiseKey
+func (i *inputComponent) SetForwardAxis(axis string) {
+	i.forwardAxis = axis
 }
 
-func (i *inputComponent) SetCounterClockwiseKey(key uint8) {
-	i.counterClockwiseKey = key
+func (i *inputComponent) GetAngularAxis() string {
+	return i.angularAxis
 }
 
-func (i *inputComponent) Destroy() {
-	owner := i.GetOwner()
-	owner.RemoveComponent(i)
+func (i *inputComponent) SetAngularAxis(axis string) {
+	i.angularAxis = axis
 }
@@ -0,0 +1,299 @@
+package chapter05
+
+import (
+	"github.com/ishtaka/go-game-programming/chapter05/math"
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+type ButtonState int
+
+const (
+	None ButtonState = iota
+	Pressed
+	Released
+	Held
+)
+
+// buttonState compares a button's previous and current values
+func buttonState(prev, curr bool) ButtonState {
+	switch {
+	case !prev && curr:
+		return Pressed
+	case prev && !curr:
+		return Released
+	case prev && curr:
+		return Held
+	default:
+		return None
+	}
+}
+
+type KeyboardState struct {
+	currState [sdl.NUM_SCANCODES]uint8
+	prevState [sdl.NUM_SCANCODES]uint8
+}
+
+// GetKeyValue reports whether the key is down this frame.
+func (k *KeyboardState) GetKeyValue(key sdl.Scancode) bool {
+	return k.currState[key] == 1
+}
+
+// GetKeyState compares the key against the previous frame.
+func (k *KeyboardState) GetKeyState(key sdl.Scancode) ButtonState {
+	return buttonState(k.prevState[key] == 1, k.currState[key] == 1)
+}
+
+type MouseState struct {
+	// Position in window coordinates, or the movement this frame in relative mode
+	mousePos    math.Vector2
+	scrollWheel math.Vector2
+	currButtons uint32
+	prevButtons uint32
+	isRelative  bool
+}
+
+func (m *MouseState) GetPosition() math.Vector2 {
+	return m.mousePos
+}
+
+func (m *MouseState) GetScrollWheel() math.Vector2 {
+	return m.scrollWheel
+}
+
+func (m *MouseState) IsRelative() bool {
+	return m.isRelative
+}
+
+// GetButtonValue reports whether the button (sdl.BUTTON_LEFT, ...) is down this frame.
+func (m *MouseState) GetButtonValue(button uint32) bool {
+	return m.currButtons&sdl.Button(button) != 0
+}
+
+// GetButtonState compares the button against the previous frame.
+func (m *MouseState) GetButtonState(button uint32) ButtonState {
+	mask := sdl.Button(button)
+	return buttonState(m.prevButtons&mask != 0, m.currButtons&mask != 0)
+}
+
+type ControllerState struct {
+	currButtons [sdl.CONTROLLER_BUTTON_MAX]uint8
+	prevButtons [sdl.CONTROLLER_BUTTON_MAX]uint8
+	// Sticks are in [-1, 1] and triggers in [0, 1], with dead zones removed
+	leftStick    math.Vector2
+	rightStick   math.Vector2
+	leftTrigger  float32
+	rightTrigger float32
+	isConnected  bool
+}
+
+func (c *ControllerState) GetButtonValue(button sdl.GameControllerButton) bool {
+	return c.currButtons[button] == 1
+}
+
+func (c *ControllerState) GetButtonState(button sdl.GameControllerButton) ButtonState {
+	return buttonState(c.prevButtons[button] == 1, c.currButtons[button] == 1)
+}
+
+func (c *ControllerState) GetLeftStick() math.Vector2 {
+	return c.leftStick
+}
+
+func (c *ControllerState) GetRightStick() math.Vector2 {
+	return c.rightStick
+}
+
+func (c *ControllerState) GetLeftTrigger() float32 {
+	return c.leftTrigger
+}
+
+func (c *ControllerState) GetRightTrigger() float32 {
+	return c.rightTrigger
+}
+
+func (c *ControllerState) IsConnected() bool {
+	return c.isConnected
+}
+
+// InputState is a snapshot of every input device for one frame.
+type InputState struct {
+	Keyboard   KeyboardState
+	Mouse      MouseState
+	Controller ControllerState
+}
+
+type InputSystem struct {
+	state      InputState
+	controller *sdl.GameController
+}
+
+func NewInputSystem() *InputSystem {
+	return &InputSystem{}
+}
+
+func (s *InputSystem) Initialize() error {
+	// Use the first connected controller, if any
+	s.openController()
+
+	return nil
+}
+
+func (s *InputSystem) Shutdown() {
+	if s.controller != nil {
+		s.controller.Close()
+		s.controller = nil
+	}
+}
+
+// PrepareForUpdate is called right before SDL events are polled.
+func (s *InputSystem) PrepareForUpdate() {
+	// Copy current state to previous
+	s.state.Keyboard.prevState = s.state.Keyboard.currState
+	s.state.Mouse.prevButtons = s.state.Mouse.currButtons
+	s.state.Mouse.scrollWheel = math.Vector2{}
+	s.state.Controller.prevButtons = s.state.Controller.currButtons
+}
+
+// Update is called right after SDL events are polled.
+func (s *InputSystem) Update() {
+	// Keyboard
+	copy(s.state.Keyboard.currState[:], sdl.GetKeyboardState())
+
+	// Mouse
+	var x, y int32
+	if s.state.Mouse.isRelative {
+		x, y, s.state.Mouse.currButtons = sdl.GetRelativeMouseState()
+	} else {
+		x, y, s.state.Mouse.currButtons = sdl.GetMouseState()
+	}
+	s.state.Mouse.mousePos = math.Vector2{X: float32(x), Y: float32(y)}
+
+	// Controller
+	c := &s.state.Controller
+	c.isConnected = s.controller != nil
+	if !c.isConnected {
+		c.currButtons = [sdl.CONTROLLER_BUTTON_MAX]uint8{}
+		c.leftStick = math.Vector2{}
+		c.rightStick = math.Vector2{}
+		c.leftTrigger = 0
+		c.rightTrigger = 0
+		return
+	}
+
+	for i := range c.currButtons {
+		c.currButtons[i] = s.controller.Button(sdl.GameControllerButton(i))
+	}
+
+	c.leftTrigger = filter1D(s.controller.Axis(sdl.CONTROLLER_AXIS_TRIGGERLEFT))
+	c.rightTrigger = filter1D(s.controller.Axis(sdl.CONTROLLER_AXIS_TRIGGERRIGHT))
+
+	c.leftStick = filter2D(
+		s.controller.Axis(sdl.CONTROLLER_AXIS_LEFTX),
+		s.controller.Axis(sdl.CONTROLLER_AXIS_LEFTY),
+	)
+	c.rightStick = filter2D(
+		s.controller.Axis(sdl.CONTROLLER_AXIS_RIGHTX),
+		s.controller.Axis(sdl.CONTROLLER_AXIS_RIGHTY),
+	)
+}
+
+// ProcessEvent handles events that aren't reflected in SDL's device state.
+func (s *InputSystem) ProcessEvent(event sdl.Event) {
+	switch e := event.(type) {
+	case *sdl.MouseWheelEvent:
+		s.state.Mouse.scrollWheel = math.Vector2{X: float32(e.X), Y: float32(e.Y)}
+	case *sdl.ControllerDeviceEvent:
+		switch e.Type {
+		case sdl.CONTROLLERDEVICEADDED:
+			if s.controller == nil {
+				s.openController()
+			}
+		case sdl.CONTROLLERDEVICEREMOVED:
+			if s.controller != nil && s.controller.Joystick().InstanceID() == e.Which {
+				s.Shutdown()
+				s.openController()
+			}
+		}
+	}
+}
+
+func (s *InputSystem) GetState() *InputState {
+	return &s.state
+}
+
+// SetState replaces this frame's input with another snapshot, such as a
+// recorded one. Call it after PrepareForUpdate, in place of Update.
+func (s *InputSystem) SetState(state *InputState) {
+	s.state.Keyboard.currState = state.Keyboard.currState
+
+	s.state.Mouse.mousePos = state.Mouse.mousePos
+	s.state.Mouse.scrollWheel = state.Mouse.scrollWheel
+	s.state.Mouse.currButtons = state.Mouse.currButtons
+	s.state.Mouse.isRelative = state.Mouse.isRelative
+
+	s.state.Controller.currButtons = state.Controller.currButtons
+	s.state.Controller.leftStick = state.Controller.leftStick
+	s.state.Controller.rightStick = state.Controller.rightStick
+	s.state.Controller.leftTrigger = state.Controller.leftTrigger
+	s.state.Controller.rightTrigger = state.Controller.rightTrigger
+	s.state.Controller.isConnected = state.Controller.isConnected
+}
+
+func (s *InputSystem) SetRelativeMouseMode(value bool) {
+	sdl.SetRelativeMouseMode(value)
+	s.state.Mouse.isRelative = value
+}
+
+func (s *InputSystem) openController() {
+	for i := range sdl.NumJoysticks() {
+		if sdl.IsGameController(i) {
+			s.controller = sdl.GameControllerOpen(i)
+			if s.controller != nil {
+				return
+			}
+		}
+	}
+}
+
+const (
+	// Axis values inside the dead zone are treated as 0,
+	// and values past the max as fully pushed
+	deadZone1D = 250
+	deadZone2D = 8000
+	maxValue   = 30000
+)
+
+func filter1D(input int16) float32 {
+	value := math.Abs(float32(input))
+
+	f := float32(0.0)
+	if value > deadZone1D {
+		// Compute fractional value between dead zone and max value
+		f = (value - deadZone1D) / (maxValue - deadZone1D)
+		// Make sure sign matches original value
+		if input < 0 {
+			f = -f
+		}
+		// Clamp between -1.0f and 1.0f
+		f = math.Clamp(f, -1.0, 1.0)
+	}
+
+	return f
+}
+
+func filter2D(inputX, inputY int16) math.Vector2 {
+	// Negate y so that up is positive
+	dir := math.Vector2{X: float32(inputX), Y: -float32(inputY)}
+	length := dir.Length()
+
+	// If length < deadZone, should be no input
+	if length < deadZone2D {
+		return math.Vector2{}
+	}
+
+	// Calculate fractional value between dead zone and max value circles
+	f := (length - deadZone2D) / (maxValue - deadZone2D)
+	// Clamp f between 0.0f and 1.0f
+	f = math.Clamp(f, 0.0, 1.0)
+	// Normalize the vector, and then scale it to the fractional value
+	return dir.MulScalar(f / length)
+}
@@ -2,7 +2,6 @@ package chapter05
 
 type Laser struct {
 	Actor
-	circle     CircleComponent
 	deathTimer float32
 }
 
@@ -14,7 +13,6 @@ func NewLaser(game *Game, drawOrder int) *Laser {
 	// create a sprite component
 	sc := NewSpriteComponent(l, drawOrder)
 	sc.SetTexture(game.GetTexture("Assets/Laser.png"))
-	game.AddSprite(sc)
 	l.AddComponent(sc)
 
 	// create a move component, and set a forward speed
@@ -23,9 +21,11 @@ func NewLaser(game *Game, drawOrder int) *Laser {
 	l.AddComponent(mc)
 
 	// create a circle component
-	l.circle = NewCircleComponent(l, DefaultUpdateOrder)
-	l.circle.SetRadius(11)
-	l.AddComponent(l.circle)
+	cc := NewCircleComponent(l, DefaultUpdateOrder)
+	cc.SetRadius(11)
+	cc.SetLayer(LayerLaser)
+	cc.SetMask(LayerAsteroid)
+	l.AddComponent(cc)
 
 	game.AddActor(l)
 
@@ -44,22 +44,13 @@ func (l *Laser) UpdateActor(deltaTime float32) {
 	l.deathTimer += deltaTime
 	if l.deathTimer <= 0.0 {
 		l.SetState(Dead)
-		return
-	}
-
-	// Do we intersect with an asteroid?
-	for _, ast := range l.GetGame().GetAsteroids() {
-		// The first asteroid we intersect with,
-		// set ourselves and the asteroid to dead
-		if Intersect(l.circle, ast.GetCircle()) {
-			l.SetState(Dead)
-			ast.SetState(Dead)
-			break
-		}
 	}
 }
 
-func (l *Laser) Destroy() {
-	l.GetGame().RemoveActor(l)
-	l.DestroyComponents()
+// OnCollisionEnter destroys the laser and the first asteroid it hits
+func (l *Laser) OnCollisionEnter(other CircleComponent) {
+	if ast, ok := other.GetOwner().(*Asteroid); ok {
+		l.SetState(Dead)
+		ast.SetState(Dead)
+	}
 }
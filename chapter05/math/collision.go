@@ -0,0 +1,412 @@
+package math
+
+// Shape is a convex 2D shape. Any two shapes can be tested with Intersect,
+// and a ray or line segment can be cast against any shape.
+type Shape interface {
+	// Contains reports whether p is inside the shape or on its edge
+	Contains(p Vector2) bool
+	// ClosestPoint returns the point of the shape closest to p (p itself if it's inside)
+	ClosestPoint(p Vector2) Vector2
+	// corners returns the corners in order around the shape, or nil for a circle
+	corners() []Vector2
+}
+
+// Circle is a circle around a center point.
+type Circle struct {
+	Center Vector2
+	Radius float32
+}
+
+func (c Circle) Contains(p Vector2) bool {
+	return p.Sub(c.Center).LengthSq() <= c.Radius*c.Radius
+}
+
+func (c Circle) ClosestPoint(p Vector2) Vector2 {
+	if c.Contains(p) {
+		return p
+	}
+
+	return c.Center.Add(p.Sub(c.Center).Normalize().MulScalar(c.Radius))
+}
+
+func (c Circle) corners() []Vector2 {
+	return nil
+}
+
+// AABB is an axis-aligned bounding box.
+type AABB struct {
+	Min, Max Vector2
+}
+
+// UpdateMinMax grows the box to contain p.
+func (b *AABB) UpdateMinMax(p Vector2) {
+	b.Min = Vector2{Min(b.Min.X, p.X), Min(b.Min.Y, p.Y)}
+	b.Max = Vector2{Max(b.Max.X, p.X), Max(b.Max.Y, p.Y)}
+}
+
+func (b AABB) Contains(p Vector2) bool {
+	return p.X >= b.Min.X && p.X <= b.Max.X && p.Y >= b.Min.Y && p.Y <= b.Max.Y
+}
+
+func (b AABB) ClosestPoint(p Vector2) Vector2 {
+	return Vector2{
+		X: Clamp(p.X, b.Min.X, b.Max.X),
+		Y: Clamp(p.Y, b.Min.Y, b.Max.Y),
+	}
+}
+
+func (b AABB) corners() []Vector2 {
+	return []Vector2{
+		b.Min,
+		{b.Max.X, b.Min.Y},
+		b.Max,
+		{b.Min.X, b.Max.Y},
+	}
+}
+
+// OBB is a box rotated about its center.
+type OBB struct {
+	Center Vector2
+	// Half the width and height of the box
+	HalfExtents Vector2
+	Rotation    Angle
+}
+
+// axes returns the box's local x and y axes in world space
+func (b OBB) axes() (Vector2, Vector2) {
+	x := Vector2{Cos(b.Rotation), Sin(b.Rotation)}
+	return x, x.Perp()
+}
+
+// toLocal returns p relative to the box's center and axes
+func (b OBB) toLocal(p Vector2) Vector2 {
+	x, y := b.axes()
+	d := p.Sub(b.Center)
+	return Vector2{d.Dot(x), d.Dot(y)}
+}
+
+// toWorld undoes toLocal
+func (b OBB) toWorld(p Vector2) Vector2 {
+	x, y := b.axes()
+	return b.Center.Add(x.MulScalar(p.X)).Add(y.MulScalar(p.Y))
+}
+
+func (b OBB) Contains(p Vector2) bool {
+	local := b.toLocal(p)
+	return Abs(local.X) <= b.HalfExtents.X && Abs(local.Y) <= b.HalfExtents.Y
+}
+
+func (b OBB) ClosestPoint(p Vector2) Vector2 {
+	local := b.toLocal(p)
+	local.X = Clamp(local.X, -b.HalfExtents.X, b.HalfExtents.X)
+	local.Y = Clamp(local.Y, -b.HalfExtents.Y, b.HalfExtents.Y)
+	return b.toWorld(local)
+}
+
+func (b OBB) corners() []Vector2 {
+	e := b.HalfExtents
+	return []Vector2{
+		b.toWorld(Vector2{-e.X, -e.Y}),
+		b.toWorld(Vector2{e.X, -e.Y}),
+		b.toWorld(Vector2{e.X, e.Y}),
+		b.toWorld(Vector2{-e.X, e.Y}),
+	}
+}
+
+// LineSegment is the line between two points.
+type LineSegment struct {
+	Start, End Vector2
+}
+
+// PointOnSegment returns the point at t along the segment (0 is Start, 1 is End).
+func (s LineSegment) PointOnSegment(t float32) Vector2 {
+	return s.Start.Lerp(s.End, t)
+}
+
+func (s LineSegment) Contains(p Vector2) bool {
+	return NearZero(s.ClosestPoint(p).Sub(p).LengthSq())
+}
+
+func (s LineSegment) ClosestPoint(p Vector2) Vector2 {
+	ab := s.End.Sub(s.Start)
+	lenSq := ab.LengthSq()
+	if NearZero(lenSq) {
+		return s.Start
+	}
+
+	t := Clamp(p.Sub(s.Start).Dot(ab)/lenSq, 0, 1)
+	return s.PointOnSegment(t)
+}
+
+func (s LineSegment) corners() []Vector2 {
+	return []Vector2{s.Start, s.End}
+}
+
+// ConvexPolygon is a convex shape with corners in order,
+// clockwise or counter-clockwise.
+type ConvexPolygon struct {
+	Vertices []Vector2
+}
+
+func (c ConvexPolygon) Contains(p Vector2) bool {
+	// Inside if p is on the same side of every edge
+	var sign float32
+	for i, a := range c.Vertices {
+		b := c.Vertices[(i+1)%len(c.Vertices)]
+		cross := b.Sub(a).Cross(p.Sub(a))
+		if NearZero(cross) {
+			continue
+		}
+		if sign == 0 {
+			sign = cross
+		} else if (sign > 0) != (cross > 0) {
+			return false
+		}
+	}
+
+	return len(c.Vertices) > 0
+}
+
+// ClosestPoint returns the point of the polygon closest to p,
+// or p itself if the polygon has no vertices.
+func (c ConvexPolygon) ClosestPoint(p Vector2) Vector2 {
+	if len(c.Vertices) == 0 || c.Contains(p) {
+		return p
+	}
+
+	return closestOnEdges(c.Vertices, p)
+}
+
+func (c ConvexPolygon) corners() []Vector2 {
+	return c.Vertices
+}
+
+// closestOnEdges returns the point on the edges of a polygon closest to p
+func closestOnEdges(verts []Vector2, p Vector2) Vector2 {
+	best := verts[0]
+	bestDistSq := float32(Infinity)
+	for i, a := range verts {
+		b := verts[(i+1)%len(verts)]
+		q := LineSegment{a, b}.ClosestPoint(p)
+		if distSq := q.Sub(p).LengthSq(); distSq < bestDistSq {
+			best = q
+			bestDistSq = distSq
+		}
+	}
+
+	return best
+}
+
+// Intersect reports whether two shapes overlap (touching counts).
+func Intersect(a, b Shape) bool {
+	// A polygon with no vertices has nothing to overlap
+	if isEmpty(a) || isEmpty(b) {
+		return false
+	}
+
+	// A circle hits a shape if the closest point of the shape is within the radius
+	if c, ok := a.(Circle); ok {
+		return c.Contains(b.ClosestPoint(c.Center))
+	}
+	if c, ok := b.(Circle); ok {
+		return c.Contains(a.ClosestPoint(c.Center))
+	}
+
+	// Anything else is a polygon, so use the separating axis theorem:
+	// they don't overlap if there's an axis their projections don't overlap on
+	pa, pb := a.corners(), b.corners()
+	for _, axis := range append(separatingAxes(pa), separatingAxes(pb)...) {
+		minA, maxA := project(pa, axis)
+		minB, maxB := project(pb, axis)
+		if maxA < minB || maxB < minA {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isEmpty reports whether s is a polygon with no vertices
+func isEmpty(s Shape) bool {
+	p, ok := s.(ConvexPolygon)
+	return ok && len(p.Vertices) == 0
+}
+
+// separatingAxes returns the axes to test for a polygon: the normal of each edge,
+// and for a line segment its direction too (since it has no width)
+func separatingAxes(verts []Vector2) []Vector2 {
+	axes := make([]Vector2, 0, len(verts)+1)
+	for i, a := range verts {
+		edge := verts[(i+1)%len(verts)].Sub(a)
+		axes = append(axes, edge.Perp())
+	}
+	if len(verts) == 2 {
+		axes = append(axes, verts[1].Sub(verts[0]))
+	}
+
+	return axes
+}
+
+// project returns the range of the corners projected onto axis
+func project(verts []Vector2, axis Vector2) (min, max float32) {
+	min = verts[0].Dot(axis)
+	max = min
+	for _, v := range verts[1:] {
+		d := v.Dot(axis)
+		min = Min(min, d)
+		max = Max(max, d)
+	}
+
+	return min, max
+}
+
+// CastHit is where a cast first hits a shape.
+type CastHit struct {
+	// How far along the cast the hit is: the fraction of the segment for
+	// SegmentCast, or the multiple of the direction for RayCast
+	T      float32
+	Point  Vector2
+	Normal Vector2
+}
+
+// SegmentCast finds where a line segment, going from Start to End, first hits a shape.
+// A segment starting inside the shape hits at Start, with the normal facing back along it.
+func SegmentCast(s LineSegment, shape Shape) (CastHit, bool) {
+	return cast(s.Start, s.End.Sub(s.Start), 1, shape)
+}
+
+// RayCast finds where a ray from origin in direction first hits a shape.
+// A ray starting inside the shape hits at origin, with the normal facing back along it.
+func RayCast(origin, direction Vector2, shape Shape) (CastHit, bool) {
+	return cast(origin, direction, float32(Infinity), shape)
+}
+
+// cast finds the first hit of origin + t*dir, for t in [0, maxT]
+func cast(origin, dir Vector2, maxT float32, shape Shape) (CastHit, bool) {
+	if NearZero(dir.LengthSq()) {
+		return CastHit{}, false
+	}
+
+	if shape.Contains(origin) {
+		return CastHit{T: 0, Point: origin, Normal: dir.MulScalar(-1).Normalize()}, true
+	}
+
+	var t float32
+	var normal Vector2
+	var ok bool
+	switch s := shape.(type) {
+	case Circle:
+		t, normal, ok = castCircle(origin, dir, s)
+	case LineSegment:
+		t, normal, ok = castSegment(origin, dir, s)
+	default:
+		t, normal, ok = castPolygon(origin, dir, shape.corners())
+	}
+	if !ok || t > maxT {
+		return CastHit{}, false
+	}
+
+	return CastHit{T: t, Point: origin.Add(dir.MulScalar(t)), Normal: normal}, true
+}
+
+func castCircle(origin, dir Vector2, c Circle) (float32, Vector2, bool) {
+	// Solve |origin + t*dir - center| = radius for the smaller t
+	x := origin.Sub(c.Center)
+	a := dir.Dot(dir)
+	b := 2 * x.Dot(dir)
+	cc := x.Dot(x) - c.Radius*c.Radius
+	disc := b*b - 4*a*cc
+	if disc < 0 {
+		return 0, Vector2{}, false
+	}
+
+	t := (-b - Sqrt(disc)) / (2 * a)
+	if t < 0 {
+		return 0, Vector2{}, false
+	}
+
+	normal := origin.Add(dir.MulScalar(t)).Sub(c.Center).Normalize()
+	return t, normal, true
+}
+
+func castSegment(origin, dir Vector2, s LineSegment) (float32, Vector2, bool) {
+	edge := s.End.Sub(s.Start)
+	denom := dir.Cross(edge)
+	if NearZero(denom) {
+		// Parallel, and origin isn't on the segment (Contains was checked),
+		// so the only hit is the near end of a segment on the same line
+		if !NearZero(s.Start.Sub(origin).Cross(dir)) {
+			return 0, Vector2{}, false
+		}
+		lenSq := dir.LengthSq()
+		t0 := s.Start.Sub(origin).Dot(dir) / lenSq
+		t1 := s.End.Sub(origin).Dot(dir) / lenSq
+		t := Min(t0, t1)
+		if t < 0 {
+			return 0, Vector2{}, false
+		}
+		return t, dir.MulScalar(-1).Normalize(), true
+	}
+
+	// Solve origin + t*dir = s.Start + u*edge
+	d := s.Start.Sub(origin)
+	t := d.Cross(edge) / denom
+	u := d.Cross(dir) / denom
+	if t < 0 || u < 0 || u > 1 {
+		return 0, Vector2{}, false
+	}
+
+	// The normal of the segment that faces the cast
+	normal := edge.Perp().Normalize()
+	if normal.Dot(dir) > 0 {
+		normal = normal.MulScalar(-1)
+	}
+
+	return t, normal, true
+}
+
+// castPolygon clips the cast against each edge (Cyrus-Beck)
+func castPolygon(origin, dir Vector2, verts []Vector2) (float32, Vector2, bool) {
+	// Edge normals point away from the middle of the polygon
+	var center Vector2
+	for _, v := range verts {
+		center = center.Add(v)
+	}
+	center = center.MulScalar(1 / float32(len(verts)))
+
+	tEnter, tExit := float32(0), float32(Infinity)
+	var normal Vector2
+	for i, a := range verts {
+		n := verts[(i+1)%len(verts)].Sub(a).Perp().Normalize()
+		if n.Dot(a.Sub(center)) < 0 {
+			n = n.MulScalar(-1)
+		}
+
+		// Distance outside this edge, and how fast the cast closes it
+		dist := origin.Sub(a).Dot(n)
+		rate := dir.Dot(n)
+		if NearZero(rate) {
+			if dist > 0 {
+				// Parallel to and outside this edge
+				return 0, Vector2{}, false
+			}
+			continue
+		}
+
+		t := -dist / rate
+		if rate < 0 {
+			// Going in through this edge
+			if t > tEnter {
+				tEnter = t
+				normal = n
+			}
+		} else {
+			tExit = Min(tExit, t)
+		}
+		if tEnter > tExit {
+			return 0, Vector2{}, false
+		}
+	}
+
+	return tEnter, normal, true
+}
@@ -6,28 +6,39 @@ import (
 	"github.com/ishtaka/go-game-programming/chapter05/math"
 )
 
+// defaultRNG backs the package functions
+var defaultRNG = NewRNG(rand.Uint64())
+
+// Default returns the generator used by the package functions.
+func Default() *RNG {
+	return defaultRNG
+}
+
+// Seed restarts the default generator, so the same seed gives the same numbers.
+func Seed(seed uint64) {
+	defaultRNG.Seed(seed)
+}
+
 func GetFloat() float32 {
-	return rand.Float32()
+	return defaultRNG.GetStream(StreamDefault).GetFloat()
 }
 
 func GetFloatRange(min, max float32) float32 {
-	return min + (max-min)*rand.Float32()
+	return defaultRNG.GetStream(StreamDefault).GetFloatRange(min, max)
 }
 
 func GetInt() int {
-	return rand.Int()
+	return defaultRNG.GetStream(StreamDefault).GetInt()
 }
 
 func GetIntRange(min, max int) int {
-	return min + rand.IntN(max-min)
+	return defaultRNG.GetStream(StreamDefault).GetIntRange(min, max)
 }
 
 func GetVector2(min, max math.Vector2) math.Vector2 {
-	v := math.Vector2{X: GetFloat(), Y: GetFloat()}
-	return min.Add(v.Mul(max.Sub(min)))
+	return defaultRNG.GetStream(StreamDefault).GetVector2(min, max)
 }
 
 func GetVector3(min, max math.Vector3) math.Vector3 {
-	v := math.Vector3{X: GetFloat(), Y: GetFloat(), Z: GetFloat()}
-	return min.Add(v.Mul(max.Sub(min)))
+	return defaultRNG.GetStream(StreamDefault).GetVector3(min, max)
 }
@@ -54,6 +54,16 @@ func (a Vector2) Dot(b Vector2) float32 {
 	return (a.X * b.X) + (a.Y * b.Y)
 }
 
+// Cross returns the z component of the cross product of a and b as 3D vectors
+func (a Vector2) Cross(b Vector2) float32 {
+	return (a.X * b.Y) - (a.Y * b.X)
+}
+
+// Perp returns a rotated 90 degrees counter-clockwise
+func (a Vector2) Perp() Vector2 {
+	return Vector2{-a.Y, a.X}
+}
+
 // Lerp returns linear interpolation from a to b by f
 func (a Vector2) Lerp(b Vector2, f float32) Vector2 {
 	return a.Add(b.Sub(a).MulScalar(f))
@@ -38,6 +38,7 @@ func (m *moveComponent) Update(deltaTime float32) {
 		pos = pos.Add(forward.MulScalar(m.forwardSpeed * deltaTime))
 
 		// (screen wrapping code only asteroids)
+		wrapped := pos.X < -512 || pos.X > 512 || pos.Y < -384 || pos.Y > 384
 		if pos.X < -512 {
 			pos.X = 510
 		} else if pos.X > 512 {
@@ -51,6 +52,10 @@ func (m *moveComponent) Update(deltaTime float32) {
 		}
 
 		m.GetOwner().SetPosition(pos)
+		if wrapped {
+			// Don't interpolate across the screen
+			m.GetOwner().SavePreviousTransform()
+		}
 	}
 }
 
@@ -69,8 +74,3 @@ func (m *moveComponent) GetForwardSpeed() float32 {
 func (m *moveComponent) SetForwardSpeed(speed float32) {
 	m.forwardSpeed = speed
 }
-
-func (m *moveComponent) Destroy() {
-	owner := m.GetOwner()
-	owner.RemoveComponent(m)
-}
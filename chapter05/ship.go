@@ -2,7 +2,6 @@ package chapter05
 
 import (
 	"github.com/ishtaka/go-game-programming/chapter05/math"
-	"github.com/veandco/go-sdl2/sdl"
 )
 
 type Ship struct {
@@ -18,19 +17,24 @@ func NewShip(game *Game, drawOrder int) *Ship {
 	// create a sprite component
 	sc := NewSpriteComponent(s, drawOrder)
 	sc.SetTexture(game.GetTexture("Assets/Ship.png"))
-	game.AddSprite(sc)
 	s.AddComponent(sc)
 
-	// create an input component and set keys/speed
+	// create an input component and set axes/speed
 	ic := NewInputComponent(s, DefaultUpdateOrder)
-	ic.SetForwardKey(sdl.SCANCODE_W)
-	ic.SetBackKey(sdl.SCANCODE_S)
-	ic.SetClockwiseKey(sdl.SCANCODE_A)
-	ic.SetCounterClockwiseKey(sdl.SCANCODE_D)
+	ic.SetForwardAxis("Thrust")
+	ic.SetAngularAxis("Turn")
 	ic.SetMaxForwardSpeed(300)
 	ic.SetMaxAngularSpeed(math.TwoPi)
 	s.AddComponent(ic)
 
+	// create a circle component on the ship's own layer, so its lasers
+	// and asteroids pass through it
+	cc := NewCircleComponent(s, DefaultUpdateOrder)
+	cc.SetRadius(32)
+	cc.SetLayer(LayerShip)
+	cc.SetMask(LayerNone)
+	s.AddComponent(cc)
+
 	game.AddActor(s)
 
 	return s
@@ -47,15 +51,15 @@ func (s *Ship) UpdateActor(deltaTime float32) {
 	s.laserCoolDown -= deltaTime
 }
 
-func (s *Ship) ProcessInput(keyState []uint8) {
-	s.Actor.ProcessInput(keyState)
+func (s *Ship) ProcessInput(state *InputState) {
+	s.Actor.ProcessInput(state)
 	if s.GetState() == Active {
-		s.ActorInput(keyState)
+		s.ActorInput(state)
 	}
 }
 
-func (s *Ship) ActorInput(keyState []uint8) {
-	if keyState[sdl.SCANCODE_SPACE] != 0 && s.laserCoolDown <= 0.0 {
+func (s *Ship) ActorInput(state *InputState) {
+	if s.GetGame().GetInputMap().GetActionValue(state, "Fire") && s.laserCoolDown <= 0.0 {
 		// Create a laser and set its position/rotation to mine
 		laser := NewLaser(s.GetGame(), DefaultUpdateOrder)
 		laser.SetPosition(s.GetPosition())
@@ -65,8 +69,3 @@ func (s *Ship) ActorInput(keyState []uint8) {
 		s.laserCoolDown = 0.5
 	}
 }
-
-func (s *Ship) Destroy() {
-	s.GetGame().RemoveActor(s)
-	s.DestroyComponents()
-}
@@ -9,7 +9,7 @@ const DefaultDrawOrder = 100
 
 type Sprite interface {
 	Component
-	Draw(shader *Shader)
+	Draw(shader *Shader, alpha float32)
 	SetTexture(tex *Texture)
 	GetDrawOrder() int
 	GetTexWidth() int32
@@ -34,11 +34,12 @@ func NewSpriteComponent(owner Actor, drawOrder int) *SpriteComponent {
 	return sc
 }
 
-func (s *SpriteComponent) Draw(shader *Shader) {
+func (s *SpriteComponent) Draw(shader *Shader, alpha float32) {
 	if s.texture != nil {
 		// Scale the quad by the width/height of texture
 		scaleMat := math.Matrix4CreateScale(float32(s.texWidth), float32(s.texHeight), 1.0)
-		world := scaleMat.Mul(s.GetOwner().GetWorldTransform())
+		// and place it between the owner's last two updates
+		world := scaleMat.Mul(s.GetOwner().GetInterpolatedWorldTransform(alpha))
 
 		// Set world transform
 		shader.SetMatrixUniform("uWorldTransform", &world)
@@ -53,6 +54,12 @@ func (s *SpriteComponent) Draw(shader *Shader) {
 
 func (s *SpriteComponent) SetTexture(tex *Texture) {
 	s.texture = tex
+	if tex == nil {
+		s.texWidth = 0
+		s.texHeight = 0
+		return
+	}
+
 	s.texWidth = tex.Width()
 	s.texHeight = tex.Height()
 }
@@ -68,9 +75,3 @@ func (s *SpriteComponent) GetTexWidth() int32 {
 func (s *SpriteComponent) GetTexHeight() int32 {
 	return s.texHeight
 }
-
-func (s *SpriteComponent) Destroy() {
-	owner := s.GetOwner()
-	owner.RemoveComponent(s)
-	owner.GetGame().RemoveSprite(s)
-}
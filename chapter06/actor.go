@@ -4,6 +4,7 @@ import (
 	"slices"
 
 	"github.com/ishtaka/go-game-programming/chapter06/math"
+	"github.com/veandco/go-sdl2/sdl"
 )
 
 type State int
@@ -31,9 +32,9 @@ type Actor interface {
 	UpdateActor(deltaTime float32)
 
 	// ProcessInput function called from Game (not overridable)
-	ProcessInput(keyState []uint8)
+	ProcessInput(state *InputState)
 	// ActorInput any actor-specific input code (overridable)
-	ActorInput(keyState []uint8)
+	ActorInput(state *InputState)
 
 	GetPosition() math.Vector3
 	SetPosition(v math.Vector3)
@@ -43,19 +44,57 @@ type Actor interface {
 	SetRotation(q *math.Quaternion)
 	GetForward() math.Vector3
 
+	// SavePreviousTransform keeps the current transform to interpolate from (called from Game)
+	SavePreviousTransform()
+	// GetInterpolatedPosition returns the position between the previous and current transform
+	GetInterpolatedPosition(alpha float32) math.Vector3
+	// GetInterpolatedRotation returns the rotation between the previous and current transform
+	GetInterpolatedRotation(alpha float32) *math.Quaternion
+	// GetInterpolatedWorldTransform returns the world transform between the previous and current transform
+	GetInterpolatedWorldTransform(alpha float32) math.Matrix4
+
 	ComputeWorldTransform()
 	GetWorldTransform() math.Matrix4
+	// GetWorldPosition returns the position after the parent's transform
+	GetWorldPosition() math.Vector3
+
+	// GetParent returns the actor this one is attached to, or nil
+	GetParent() Actor
+	// GetChildren returns the actors attached to this one
+	GetChildren() []Actor
 
 	GetState() State
 	SetState(s State)
 
 	GetGame() *Game
+	// GetTypeName returns the name of the actor in scene files (overridable)
+	GetTypeName() string
+
+	// LoadProperties reads the actor's state and transform from a scene file (overridable)
+	LoadProperties(props Properties)
+	// SaveProperties writes the actor's state and transform to a scene file (overridable)
+	SaveProperties(props Properties)
 
+	// AddComponent adds the component and registers it with the game
 	AddComponent(c Component)
+	// RemoveComponent removes the component and unregisters it from the game
 	RemoveComponent(c Component)
-
-	Destroy()           // must override if embedded in a struct
-	DestroyComponents() // must be called in Destroy if embedded in a struct
+	// GetComponents returns the components in update order
+	GetComponents() []Component
+
+	// OnAdded called after the actor is added to the game (overridable)
+	OnAdded()
+	// OnRemoved called after the actor is removed from the game (overridable)
+	OnRemoved()
+	// OnDestroy called when the actor is destroyed, before its components are removed (overridable)
+	OnDestroy()
+
+	// Set through AddChild and RemoveChild
+	setParent(parent Actor)
+	addChild(child Actor)
+	removeChild(child Actor)
+	// invalidateWorldTransform marks the world transform of the actor and its children as stale
+	invalidateWorldTransform()
 }
 
 type actor struct {
@@ -68,6 +107,13 @@ type actor struct {
 	scale                   float32
 	rotation                *math.Quaternion
 	recomputeWorldTransform bool
+	// Transform before the last update, for interpolation
+	prevPosition math.Vector3
+	prevRotation *math.Quaternion
+
+	// Hierarchy, with the transform relative to the parent
+	parent   Actor
+	children []Actor
 
 	components []Component
 	game       *Game
@@ -80,6 +126,7 @@ func NewActor(game *Game) Actor {
 		scale:                   1,
 		rotation:                math.QuaternionIdentity(),
 		recomputeWorldTransform: true,
+		prevRotation:            math.QuaternionIdentity(),
 		game:                    game,
 	}
 
@@ -105,18 +152,18 @@ func (a *actor) UpdateComponents(deltaTime float32) {
 
 func (a *actor) UpdateActor(deltaTime float32) {}
 
-func (a *actor) ProcessInput(keyState []uint8) {
+func (a *actor) ProcessInput(state *InputState) {
 	if a.state == Active {
 		// first process input for components
 		for _, c := range a.components {
-			c.ProcessInput(keyState)
+			c.ProcessInput(state)
 		}
 
-		a.ActorInput(keyState)
+		a.ActorInput(state)
 	}
 }
 
-func (a *actor) ActorInput(keyState []uint8) {}
+func (a *actor) ActorInput(state *InputState) {}
 
 func (a *actor) GetPosition() math.Vector3 {
 	return a.position
@@ -124,7 +171,7 @@ func (a *actor) GetPosition() math.Vector3 {
 
 func (a *actor) SetPosition(v math.Vector3) {
 	a.position = v
-	a.recomputeWorldTransform = true
+	a.invalidateWorldTransform()
 }
 
 func (a *actor) GetScale() float32 {
@@ -133,7 +180,7 @@ func (a *actor) GetScale() float32 {
 
 func (a *actor) SetScale(s float32) {
 	a.scale = s
-	a.recomputeWorldTransform = true
+	a.invalidateWorldTransform()
 }
 
 func (a *actor) GetRotation() *math.Quaternion {
@@ -142,7 +189,7 @@ func (a *actor) GetRotation() *math.Quaternion {
 
 func (a *actor) SetRotation(q *math.Quaternion) {
 	a.rotation = q
-	a.recomputeWorldTransform = true
+	a.invalidateWorldTransform()
 }
 
 func (a *actor) GetForward() math.Vector3 {
@@ -152,11 +199,13 @@ func (a *actor) GetForward() math.Vector3 {
 func (a *actor) ComputeWorldTransform() {
 	if a.recomputeWorldTransform {
 		a.recomputeWorldTransform = false
-		// Scale, then rotate, then translate
-		scale := math.Matrix4CreateUniScale(a.scale)
-		rotation := math.Matrix4CreateFromQuaternion(a.rotation)
-		translation := math.Matrix4CreateTranslation(a.position)
-		a.worldTransform = scale.Mul(rotation).Mul(translation)
+		a.worldTransform = a.computeTransform(a.position, a.rotation)
+
+		// Then apply the parent's transform
+		if a.parent != nil {
+			a.parent.ComputeWorldTransform()
+			a.worldTransform = a.worldTransform.Mul(a.parent.GetWorldTransform())
+		}
 
 		// Inform components world transform updated
 		for _, c := range a.components {
@@ -169,18 +218,97 @@ func (a *actor) GetWorldTransform() math.Matrix4 {
 	return a.worldTransform
 }
 
+func (a *actor) GetWorldPosition() math.Vector3 {
+	a.ComputeWorldTransform()
+	return a.worldTransform.GetTranslation()
+}
+
+func (a *actor) SavePreviousTransform() {
+	a.prevPosition = a.position
+	a.prevRotation = a.rotation
+}
+
+func (a *actor) GetInterpolatedPosition(alpha float32) math.Vector3 {
+	return a.prevPosition.Lerp(a.position, alpha)
+}
+
+func (a *actor) GetInterpolatedRotation(alpha float32) *math.Quaternion {
+	return a.prevRotation.Slerp(a.rotation, alpha)
+}
+
+func (a *actor) GetInterpolatedWorldTransform(alpha float32) math.Matrix4 {
+	transform := a.computeTransform(a.GetInterpolatedPosition(alpha), a.GetInterpolatedRotation(alpha))
+	if a.parent != nil {
+		transform = transform.Mul(a.parent.GetInterpolatedWorldTransform(alpha))
+	}
+
+	return transform
+}
+
+func (a *actor) computeTransform(position math.Vector3, rotation *math.Quaternion) math.Matrix4 {
+	// Scale, then rotate, then translate
+	scale := math.Matrix4CreateUniScale(a.scale)
+	rot := math.Matrix4CreateFromQuaternion(rotation)
+	translation := math.Matrix4CreateTranslation(position)
+	return scale.Mul(rot).Mul(translation)
+}
+
 func (a *actor) GetState() State {
 	return a.state
 }
 
 func (a *actor) SetState(s State) {
+	wasActive := a.state == Active
 	a.state = s
+
+	// Let components know when the actor starts or stops being active
+	if isActive := s == Active; isActive != wasActive {
+		for _, c := range a.components {
+			if isActive {
+				c.OnEnable()
+			} else {
+				c.OnDisable()
+			}
+		}
+	}
 }
 
 func (a *actor) GetGame() *Game {
 	return a.game
 }
 
+func (a *actor) GetTypeName() string {
+	return "Actor"
+}
+
+func (a *actor) LoadProperties(props Properties) {
+	var state string
+	if props.GetString("state", &state) {
+		for _, s := range []State{Active, Paused, Dead} {
+			if state == s.String() {
+				a.SetState(s)
+			}
+		}
+	}
+
+	props.GetVector3("position", &a.position)
+	if q, ok := props.GetQuaternion("rotation"); ok {
+		a.rotation = q
+	}
+	props.GetFloat("scale", &a.scale)
+
+	// Start interpolating from the loaded transform
+	a.SavePreviousTransform()
+	a.invalidateWorldTransform()
+}
+
+func (a *actor) SaveProperties(props Properties) {
+	props.SetString("state", a.state.String())
+	props.SetVector3("position", a.position)
+	props.SetQuaternion("rotation", a.rotation)
+	props.SetFloat("scale", a.scale)
+}
+
 func (a *actor) AddComponent(c Component) {
 	order := c.GetUpdateOrder()
 	insertIndex := 0
@@ -193,21 +321,121 @@ func (a *actor) AddComponent(c Component) {
 
 	// Insert at position
 	a.components = slices.Insert(a.components, insertIndex, c)
+
+	a.game.addComponent(c)
+	c.OnAdded()
 }
 
 func (a *actor) RemoveComponent(c Component) {
+	if !slices.Contains(a.components, c) {
+		return
+	}
+
 	a.components = slices.DeleteFunc(a.components, func(c2 Component) bool {
 		return c == c2
 	})
+
+	a.game.removeComponent(c)
+	c.OnRemoved()
+}
+
+func (a *actor) GetComponents() []Component {
+	return a.components
+}
+
+// GetComponent returns the first component of the actor that is a T.
+func GetComponent[T Component](a Actor) (T, bool) {
+	for _, c := range a.GetComponents() {
+		if t, ok := c.(T); ok {
+			return t, true
+		}
+	}
+
+	var zero T
+	return zero, false
+}
+
+// GetComponents returns every component of the actor that is a T.
+func GetComponents[T Component](a Actor) []T {
+	var found []T
+	for _, c := range a.GetComponents() {
+		if t, ok := c.(T); ok {
+			found = append(found, t)
+		}
+	}
+
+	return found
+}
+
+// HasComponent reports whether the actor has a component that is a T.
+func HasComponent[T Component](a Actor) bool {
+	_, ok := GetComponent[T](a)
+	return ok
+}
+
+func (a *actor) GetParent() Actor {
+	return a.parent
+}
+
+func (a *actor) GetChildren() []Actor {
+	return a.children
+}
+
+func (a *actor) setParent(parent Actor) {
+	a.parent = parent
+}
+
+func (a *actor) addChild(child Actor) {
+	a.children = append(a.children, child)
+}
+
+func (a *actor) removeChild(child Actor) {
+	a.children = slices.DeleteFunc(a.children, func(c Actor) bool {
+		return c == child
+	})
+}
+
+func (a *actor) invalidateWorldTransform() {
+	a.recomputeWorldTransform = true
+	for _, child := range a.children {
+		child.invalidateWorldTransform()
+	}
 }
 
-func (a *actor) Destroy() {
-	a.game.RemoveActor(a)
-	a.DestroyComponents()
+// AddChild attaches child to parent. The child's position, rotation and scale
+// become relative to the parent, and it's destroyed along with the parent.
+func AddChild(parent, child Actor) {
+	// An actor can't be attached to itself or its own descendants
+	for p := parent; p != nil; p = p.GetParent() {
+		if p == child {
+			sdl.Log("cannot attach an actor below itself")
+			return
+		}
+	}
+
+	if old := child.GetParent(); old != nil {
+		RemoveChild(old, child)
+	}
+
+	child.setParent(parent)
+	parent.addChild(child)
+	child.invalidateWorldTransform()
 }
 
-func (a *actor) DestroyComponents() {
-	for len(a.components) > 0 {
-		a.components[0].Destroy()
+// RemoveChild detaches child from parent. The child's transform
+// is relative to the world again.
+func RemoveChild(parent, child Actor) {
+	if child.GetParent() != parent {
+		return
 	}
+
+	parent.removeChild(child)
+	child.setParent(nil)
+	child.invalidateWorldTransform()
 }
+
+func (a *actor) OnAdded() {}
+
+func (a *actor) OnRemoved() {}
+
+func (a *actor) OnDestroy() {}
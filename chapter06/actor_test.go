@@ -0,0 +1,117 @@
+package chapter06
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/ishtaka/go-game-programming/chapter06/math"
+)
+
+func newHeadlessGame(t *testing.T) *Game {
+	t.Helper()
+
+	g := NewHeadlessGame(NewManualClock())
+	if err := g.Initialize(); err != nil {
+		t.Fatalf("failed to initialize headless game: %s", err)
+	}
+	t.Cleanup(func() {
+		_ = g.Shutdown()
+	})
+
+	return g
+}
+
+func nearlyEqual(a, b math.Vector3) bool {
+	return math.NearZero(a.Sub(b).Length())
+}
+
+func TestChildTransform(t *testing.T) {
+	g := newHeadlessGame(t)
+
+	parent := NewActor(g)
+	parent.SetPosition(math.Vector3{X: 100})
+	g.AddActor(parent)
+
+	child := NewActor(g)
+	child.SetPosition(math.Vector3{X: 10})
+	g.AddActor(child)
+	AddChild(parent, child)
+
+	if pos := child.GetWorldPosition(); !nearlyEqual(pos, math.Vector3{X: 110}) {
+		t.Errorf("expected child at (110, 0, 0), got %v", pos)
+	}
+
+	// Turning the parent a quarter turn about z swings the child around it
+	parent.SetRotation(math.NewQuaternionFromVec(math.Vector3UnitZ, math.PiOver2))
+	if pos := child.GetWorldPosition(); !nearlyEqual(pos, math.Vector3{X: 100, Y: 10}) {
+		t.Errorf("expected child at (100, 10, 0), got %v", pos)
+	}
+
+	// Scale carries down through a grandchild
+	parent.SetScale(2)
+	grandchild := NewActor(g)
+	grandchild.SetPosition(math.Vector3{X: 5})
+	g.AddActor(grandchild)
+	AddChild(child, grandchild)
+	if pos := grandchild.GetWorldPosition(); !nearlyEqual(pos, math.Vector3{X: 100, Y: 30}) {
+		t.Errorf("expected grandchild at (100, 30, 0), got %v", pos)
+	}
+
+	// Detached, the child is back in world space
+	RemoveChild(parent, child)
+	if child.GetParent() != nil || len(parent.GetChildren()) != 0 {
+		t.Errorf("expected child to be detached")
+	}
+	if pos := child.GetWorldPosition(); !nearlyEqual(pos, math.Vector3{X: 10}) {
+		t.Errorf("expected child at (10, 0, 0), got %v", pos)
+	}
+}
+
+func TestAddChildRejectsCycles(t *testing.T) {
+	g := newHeadlessGame(t)
+
+	a := NewActor(g)
+	b := NewActor(g)
+	AddChild(a, b)
+	AddChild(b, a)
+
+	if a.GetParent() != nil {
+		t.Errorf("expected an actor not to be attached below its own child")
+	}
+}
+
+func TestDestroyParentDestroysChildren(t *testing.T) {
+	g := newHeadlessGame(t)
+
+	parent := NewActor(g)
+	g.AddActor(parent)
+	child := NewActor(g)
+	g.AddActor(child)
+	AddChild(parent, child)
+	grandchild := NewActor(g)
+	g.AddActor(grandchild)
+	AddChild(child, grandchild)
+
+	g.DestroyActor(parent)
+
+	for _, a := range []Actor{parent, child, grandchild} {
+		if slices.Contains(g.GetActors(), a) {
+			t.Errorf("expected actor to be removed from the game")
+		}
+	}
+
+	// Destroying a child only detaches it from its parent
+	parent = NewActor(g)
+	g.AddActor(parent)
+	child = NewActor(g)
+	g.AddActor(child)
+	AddChild(parent, child)
+
+	g.DestroyActor(child)
+	if len(parent.GetChildren()) != 0 {
+		t.Errorf("expected destroyed child to be detached")
+	}
+	if !slices.Contains(g.GetActors(), parent) {
+		t.Errorf("expected parent to stay in the game")
+	}
+}
@@ -0,0 +1,117 @@
+package chapter06
+
+import "github.com/ishtaka/go-game-programming/chapter06/math"
+
+// BoxComponent is a collision box around its owner, which the game's PhysWorld
+// can cast segments against. Unless it's given a box, it uses the bounds of
+// the owner's mesh.
+type BoxComponent interface {
+	Component
+	// GetObjectBox returns the box before the owner's transform
+	GetObjectBox() math.AABB
+	SetObjectBox(box math.AABB)
+	// GetWorldBox returns the box after the owner's transform
+	GetWorldBox() math.AABB
+	// HasBox reports whether the component has a box yet
+	HasBox() bool
+	// SetShouldRotate sets whether the box turns with the owner (otherwise it's only scaled and moved)
+	SetShouldRotate(shouldRotate bool)
+}
+
+func NewBoxComponent(owner Actor, updateOrder int) BoxComponent {
+	c := NewComponent(owner, updateOrder)
+	bc := &boxComponent{
+		Component:    c,
+		shouldRotate: true,
+	}
+
+	return bc
+}
+
+type boxComponent struct {
+	Component
+	objectBox math.AABB
+	worldBox  math.AABB
+	// Whether objectBox was set, rather than taken from the mesh
+	hasObjectBox bool
+	shouldRotate bool
+}
+
+func (b *boxComponent) OnUpdateWorldTransform() {
+	world := b.GetOwner().GetWorldTransform()
+	if !b.shouldRotate {
+		// Just scale and translate
+		scale := world.GetScale()
+		world = math.Matrix4CreateScale(scale.X, scale.Y, scale.Z).Mul(math.Matrix4CreateTranslation(world.GetTranslation()))
+	}
+
+	b.worldBox = b.getObjectBox().Transform(world)
+}
+
+func (b *boxComponent) GetObjectBox() math.AABB {
+	return b.getObjectBox()
+}
+
+func (b *boxComponent) SetObjectBox(box math.AABB) {
+	b.objectBox = box
+	b.hasObjectBox = true
+	b.OnUpdateWorldTransform()
+}
+
+func (b *boxComponent) GetWorldBox() math.AABB {
+	return b.worldBox
+}
+
+func (b *boxComponent) HasBox() bool {
+	_, ok := b.getMesh()
+	return b.hasObjectBox || ok
+}
+
+func (b *boxComponent) SetShouldRotate(shouldRotate bool) {
+	b.shouldRotate = shouldRotate
+	b.OnUpdateWorldTransform()
+}
+
+// getObjectBox returns the box that was set, or else the mesh's bounds
+func (b *boxComponent) getObjectBox() math.AABB {
+	if b.hasObjectBox {
+		return b.objectBox
+	}
+	if mesh, ok := b.getMesh(); ok {
+		return mesh.GetBox()
+	}
+
+	return math.AABB{}
+}
+
+// getMesh returns the mesh of the owner's first mesh component that has one
+func (b *boxComponent) getMesh() (*Mesh, bool) {
+	for _, mc := range GetComponents[MeshComponent](b.GetOwner()) {
+		if mesh := mc.GetMesh(); mesh != nil {
+			return mesh, true
+		}
+	}
+
+	return nil, false
+}
+
+func (b *boxComponent) GetTypeName() string {
+	return "BoxComponent"
+}
+
+func (b *boxComponent) LoadProperties(props Properties) {
+	var box math.AABB
+	if props.GetVector3("objectMin", &box.Min) && props.GetVector3("objectMax", &box.Max) {
+		b.objectBox = box
+		b.hasObjectBox = true
+	}
+	props.GetBool("shouldRotate", &b.shouldRotate)
+}
+
+func (b *boxComponent) SaveProperties(props Properties) {
+	if b.hasObjectBox {
+		props.SetVector3("objectMin", b.objectBox.Min)
+		props.SetVector3("objectMax", b.objectBox.Max)
+	}
+	props.SetBool("shouldRotate", b.shouldRotate)
+}
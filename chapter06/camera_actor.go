@@ -2,9 +2,11 @@ package chapter06
 
 import (
 	"github.com/ishtaka/go-game-programming/chapter06/math"
-	"github.com/veandco/go-sdl2/sdl"
 )
 
+// cameraRadius is how close the camera can get to a wall.
+const cameraRadius float32 = 10.0
+
 type CameraActor struct {
 	Actor
 	moveComp MoveComponent
@@ -24,54 +26,72 @@ func NewCameraActor(game *Game) *CameraActor {
 func (c *CameraActor) Update(deltaTime float32) {
 	if c.GetState() == Active {
 		c.ComputeWorldTransform()
+		from := c.GetPosition()
 
 		c.UpdateComponents(deltaTime)
 		c.UpdateActor(deltaTime)
+		c.keepOutOfWalls(from)
 
 		c.ComputeWorldTransform()
 	}
 }
 
-func (c *CameraActor) UpdateActor(deltaTime float32) {
-	c.Actor.UpdateActor(deltaTime)
+// keepOutOfWalls stops the camera short of any box between from and where it moved to
+func (c *CameraActor) keepOutOfWalls(from math.Vector3) {
+	to := c.GetPosition()
+	if to == from {
+		return
+	}
 
-	cameraPos := c.GetPosition()
-	target := cameraPos.Add(c.GetForward().MulScalar(100.0))
+	// Cast a little further than the move, so the camera doesn't end up right against the wall
+	dir := to.Sub(from).Normalize()
+	l := math.LineSegment{Start: from, End: to.Add(dir.MulScalar(cameraRadius))}
+	if info, ok := c.GetGame().GetPhysWorld().SegmentCast(l); ok {
+		c.SetPosition(info.Point.Add(info.Normal.MulScalar(cameraRadius)))
+	}
+}
+
+// UpdateView sets the renderer's view matrix from the camera,
+// interpolated by alpha between the last two updates.
+func (c *CameraActor) UpdateView(alpha float32) {
+	cameraPos := c.GetInterpolatedPosition(alpha)
+	forward := math.Vector3UnitX.TransformByQuaternion(c.GetInterpolatedRotation(alpha))
+	target := cameraPos.Add(forward.MulScalar(100.0))
 	up := math.Vector3UnitZ
 
 	view := math.Matrix4CreateLookAt(cameraPos, target, up)
 	c.GetGame().GetRenderer().SetViewMatrix(view)
 }
 
-func (c *CameraActor) ProcessInput(keyState []uint8) {
+func (c *CameraActor) ProcessInput(state *InputState) {
 	if c.GetState() == Active {
-		c.Actor.ProcessInput(keyState)
-		c.ActorInput(keyState)
+		c.Actor.ProcessInput(state)
+		c.ActorInput(state)
 	}
 }
 
-func (c *CameraActor) ActorInput(keyState []uint8) {
-	var forwardSpeed, angularSpeed float32
+func (c *CameraActor) ActorInput(state *InputState) {
+	inputMap := c.GetGame().GetInputMap()
 
-	// wasd movement
-	if keyState[sdl.SCANCODE_W] != 0 {
-		forwardSpeed += 300.0
-	}
-	if keyState[sdl.SCANCODE_S] != 0 {
-		forwardSpeed -= 300.0
-	}
-	if keyState[sdl.SCANCODE_A] != 0 {
-		angularSpeed -= math.TwoPi
-	}
-	if keyState[sdl.SCANCODE_D] != 0 {
-		angularSpeed += math.TwoPi
-	}
+	// Movement from the "Forward" and "Turn" axes (wasd by default)
+	forwardSpeed := 300.0 * inputMap.GetAxisValue(state, "Forward")
+	angularSpeed := math.TwoPi * inputMap.GetAxisValue(state, "Turn")
 
 	c.moveComp.SetForwardSpeed(forwardSpeed)
 	c.moveComp.SetAngularSpeed(angularSpeed)
 }
 
-func (c *CameraActor) Destroy() {
-	c.GetGame().RemoveActor(c)
-	c.DestroyComponents()
+func (c *CameraActor) GetTypeName() string {
+	return "CameraActor"
+}
+
+// OnAdded makes this the game's camera
+func (c *CameraActor) OnAdded() {
+	c.GetGame().SetCamera(c)
+}
+
+func (c *CameraActor) OnRemoved() {
+	if c.GetGame().GetCamera() == c {
+		c.GetGame().SetCamera(nil)
+	}
 }
@@ -6,12 +6,27 @@ type Component interface {
 	// Update this component by delta time
 	Update(deltaTime float32)
 	// ProcessInput input for this component
-	ProcessInput(keyState []uint8)
+	ProcessInput(state *InputState)
 	// OnUpdateWorldTransform called when world transform changes
 	OnUpdateWorldTransform()
 	GetOwner() Actor
 	GetUpdateOrder() int
-	Destroy() // must override if embedded in a struct and call owner RemoveComponent
+	// GetTypeName returns the name of the component in scene files (overridable)
+	GetTypeName() string
+
+	// LoadProperties reads the component's properties from a scene file (overridable)
+	LoadProperties(props Properties)
+	// SaveProperties writes the component's properties to a scene file (overridable)
+	SaveProperties(props Properties)
+
+	// OnAdded called after the component is added to its owner
+	OnAdded()
+	// OnRemoved called after the component is removed from its owner
+	OnRemoved()
+	// OnEnable called when the owner becomes active
+	OnEnable()
+	// OnDisable called when the owner stops being active
+	OnDisable()
 }
 
 type component struct {
@@ -32,7 +47,7 @@ func NewComponent(owner Actor, updateOrder int) Component {
 
 func (c *component) Update(deltaTime float32) {}
 
-func (c *component) ProcessInput(keyState []uint8) {}
+func (c *component) ProcessInput(state *InputState) {}
 
 func (c *component) OnUpdateWorldTransform() {}
 
@@ -44,6 +59,18 @@ func (c *component) GetUpdateOrder() int {
 	return c.updateOrder
 }
 
-func (c *component) Destroy() {
-	c.owner.RemoveComponent(c)
+func (c *component) GetTypeName() string {
+	return "Component"
 }
+
+func (c *component) LoadProperties(props Properties) {}
+
+func (c *component) SaveProperties(props Properties) {}
+
+func (c *component) OnAdded() {}
+
+func (c *component) OnRemoved() {}
+
+func (c *component) OnEnable() {}
+
+func (c *component) OnDisable() {}
@@ -2,16 +2,35 @@ package chapter06
 
 import (
 	"slices"
+	"time"
 
-	"github.com/ishtaka/go-game-programming/chapter06/math"
 	"github.com/veandco/go-sdl2/sdl"
 )
 
-type Game struct {
-	renderer *Renderer
+// DefaultTickRate is the number of simulation steps per second.
+const DefaultTickRate = 60
+
+// maxFrameTime is the most time simulated in a single frame (in seconds).
+const maxFrameTime = 0.25
 
-	ticksCount uint64
-	isRunning  bool
+type Game struct {
+	renderer    *Renderer
+	inputSystem *InputSystem
+	inputMap    *InputMap
+	physWorld   *PhysWorld
+
+	clock     Clock
+	lastTime  time.Duration
+	isRunning bool
+	// Run the simulation without a window or renderer
+	headless bool
+
+	// Time between simulation steps (in seconds)
+	fixedDeltaTime float32
+	// Time not yet simulated
+	accumulator float32
+	// Fraction of a step to interpolate drawing by
+	alpha float32
 
 	// All the actors in the game
 	actors []Actor
@@ -19,16 +38,39 @@ type Game struct {
 	pendingActors []Actor
 	// Track if we're updating actors right now
 	updatingActors bool
+
+	// Camera to view the scene from
+	camera *CameraActor
 }
 
-func NewGame() *Game {
+func NewGame(clock Clock) *Game {
 	return &Game{
-		ticksCount: 0,
-		isRunning:  true,
+		clock:          clock,
+		physWorld:      NewPhysWorld(),
+		fixedDeltaTime: 1.0 / DefaultTickRate,
+		isRunning:      true,
 	}
 }
 
+// NewHeadlessGame creates a game that simulates without a window or OpenGL context.
+// Use Step to advance the simulation.
+func NewHeadlessGame(clock Clock) *Game {
+	g := NewGame(clock)
+	g.headless = true
+
+	return g
+}
+
 func (g *Game) Initialize() error {
+	if g.headless {
+		g.renderer = NewRenderer(g)
+		g.renderer.InitializeHeadless(1024.0, 768.0)
+		g.loadData()
+		g.savePreviousTransforms()
+		g.lastTime = g.clock.Now()
+		return nil
+	}
+
 	if err := sdl.Init(sdl.INIT_EVERYTHING); err != nil {
 		sdl.Log("unable to initialize SDL: %s\n", err)
 		return err
@@ -41,57 +83,108 @@ func (g *Game) Initialize() error {
 		return err
 	}
 
+	// Initialize input system
+	g.inputSystem = NewInputSystem()
+	if err := g.inputSystem.Initialize(); err != nil {
+		sdl.Log("failed to initialize input system: %s\n", err)
+		return err
+	}
+
 	g.loadData()
+	g.savePreviousTransforms()
 
-	g.ticksCount = sdl.GetTicks64()
+	g.lastTime = g.clock.Now()
 
 	return nil
 }
 
 func (g *Game) RunLoop() {
 	for g.isRunning {
+		g.RunFrame()
+	}
+}
+
+// RunFrame runs a single iteration of the game loop, simulating
+// the time passed on the clock since the previous frame.
+func (g *Game) RunFrame() {
+	if !g.headless {
 		g.processInput()
-		g.update()
+	}
+	g.update()
+	if !g.headless {
 		g.generateOutput()
 	}
 }
 
+// Step advances the simulation by deltaTime seconds,
+// without reading input or drawing.
+func (g *Game) Step(deltaTime float32) {
+	g.updateGame(deltaTime)
+}
+
+// SetTickRate sets the number of simulation steps per second.
+func (g *Game) SetTickRate(ticksPerSecond float32) {
+	g.fixedDeltaTime = 1.0 / ticksPerSecond
+}
+
+// GetTickRate returns the number of simulation steps per second.
+func (g *Game) GetTickRate() float32 {
+	return 1.0 / g.fixedDeltaTime
+}
+
 func (g *Game) processInput() {
+	g.inputSystem.PrepareForUpdate()
+
 	for event := sdl.PollEvent(); event != nil; event = sdl.PollEvent() {
 		switch event.(type) {
 		case *sdl.QuitEvent:
 			g.isRunning = false
-			return
+		default:
+			g.inputSystem.ProcessEvent(event)
 		}
+	}
 
-		state := sdl.GetKeyboardState()
-		if state[sdl.SCANCODE_ESCAPE] != 0 {
-			g.isRunning = false
-			return
-		}
+	g.inputSystem.Update()
+	state := g.inputSystem.GetState()
 
-		g.updatingActors = true
-		for _, a := range g.actors {
-			a.ProcessInput(state)
-		}
-		g.updatingActors = false
+	if state.Keyboard.GetKeyValue(sdl.SCANCODE_ESCAPE) {
+		g.isRunning = false
+		return
+	}
+
+	g.updatingActors = true
+	for _, a := range g.actors {
+		a.ProcessInput(state)
 	}
+	g.updatingActors = false
 }
 
 func (g *Game) update() {
-	// SDL_TICKS_PASSED is not available in go-sdl2
-	for {
-		if sdl.GetTicks64() > g.ticksCount+16 {
-			break
-		}
+	// Time elapsed since last frame (converted to seconds)
+	now := g.clock.Now()
+	frameTime := float32((now - g.lastTime).Seconds())
+	g.lastTime = now
+
+	// Clamp the frame time, so a slow frame can't demand more and more
+	// steps to catch up (spiral of death)
+	if frameTime > maxFrameTime {
+		frameTime = maxFrameTime
 	}
 
-	deltaTime := float32(sdl.GetTicks64()-g.ticksCount) / 1000.0
-	if deltaTime > 0.05 {
-		deltaTime = 0.05
+	// Simulate in fixed steps until the accumulated time is used up
+	g.accumulator += frameTime
+	for g.accumulator >= g.fixedDeltaTime {
+		g.updateGame(g.fixedDeltaTime)
+		g.accumulator -= g.fixedDeltaTime
 	}
 
-	g.ticksCount = sdl.GetTicks64()
+	// How far we are between the last step and the next one
+	g.alpha = g.accumulator / g.fixedDeltaTime
+}
+
+func (g *Game) updateGame(deltaTime float32) {
+	// Keep transforms from before this update to interpolate drawing from
+	g.savePreviousTransforms()
 
 	// Update all actors
 	g.updatingActors = true
@@ -103,6 +196,7 @@ func (g *Game) update() {
 	// Move any pending actors to actors
 	for _, pending := range g.pendingActors {
 		pending.ComputeWorldTransform()
+		pending.SavePreviousTransform()
 		g.actors = append(g.actors, pending)
 	}
 	g.pendingActors = nil
@@ -117,120 +211,43 @@ func (g *Game) update() {
 
 	// Delete dead actors (which removes them from actors)
 	for _, deadActor := range deadActors {
-		deadActor.Destroy()
+		// Children may have already gone with their parent
+		if slices.Contains(g.actors, deadActor) {
+			g.DestroyActor(deadActor)
+		}
 	}
 }
 
-func (g *Game) generateOutput() {
-	g.renderer.Draw()
+func (g *Game) savePreviousTransforms() {
+	for _, a := range g.actors {
+		a.SavePreviousTransform()
+	}
 }
 
-func (g *Game) loadData() {
-	a := NewActor(g)
-	a.SetPosition(math.Vector3{X: 200.0, Y: 75.0, Z: 0.0})
-	a.SetScale(100)
-	q := math.NewQuaternionFromVec(math.Vector3UnitY, -math.PiOver2)
-	q = q.Concatenate(math.NewQuaternionFromVec(math.Vector3UnitZ, math.Pi+math.Pi/4.0))
-	a.SetRotation(q)
-
-	mc := NewMeshComponent(a, DefaultUpdateOrder)
-	m := g.GetRenderer().GetMesh("Assets/Cube.gpmesh")
-	mc.SetMesh(m)
-	a.AddComponent(mc)
-	g.AddActor(a)
-
-	a = NewActor(g)
-	a.SetPosition(math.Vector3{X: 200.0, Y: -75.0, Z: 0.0})
-	a.SetScale(3)
-
-	mc = NewMeshComponent(a, DefaultUpdateOrder)
-	m = g.GetRenderer().GetMesh("Assets/Sphere.gpmesh")
-	mc.SetMesh(m)
-	a.AddComponent(mc)
-	g.AddActor(a)
-
-	// Setup floor
-	const start float32 = -1250.0
-	const size float32 = 250.0
-	for i := range 10 {
-		fi := float32(i)
-		for j := range 10 {
-			fj := float32(j)
-			p := NewPlaneActor(g)
-			p.SetPosition(math.Vector3{
-				X: start + fi*size,
-				Y: start + fj*size,
-				Z: -100,
-			})
-			g.AddActor(p)
-		}
+func (g *Game) generateOutput() {
+	// Place the camera between its last two updates as well
+	if g.camera != nil {
+		g.camera.UpdateView(g.alpha)
 	}
 
-	// Left/right walls
-	q = math.NewQuaternionFromVec(math.Vector3UnitX, math.PiOver2)
-	for i := range 10 {
-		fi := float32(i)
-
-		a = NewPlaneActor(g)
-		a.SetPosition(math.Vector3{
-			X: start + fi*size,
-			Y: start - size,
-			Z: 0.0,
-		})
-		a.SetRotation(q)
-		g.AddActor(a)
-
-		a = NewPlaneActor(g)
-		a.SetPosition(math.Vector3{
-			X: start + fi*size,
-			Y: -start + size,
-			Z: 0.0,
-		})
-		a.SetRotation(q)
-		g.AddActor(a)
-	}
+	g.renderer.Draw(g.alpha)
+}
 
-	// Forward/back walls
-	q = q.Concatenate(math.NewQuaternionFromVec(math.Vector3UnitZ, math.PiOver2))
-	for i := range 10 {
-		fi := float32(i)
-
-		a = NewPlaneActor(g)
-		a.SetPosition(math.Vector3{
-			X: start - size,
-			Y: start + fi*size,
-			Z: 0.0,
-		})
-		a.SetRotation(q)
-		g.AddActor(a)
-
-		a = NewPlaneActor(g)
-		a.SetPosition(math.Vector3{
-			X: -start + size,
-			Y: start + fi*size,
-			Z: 0.0,
-		})
-		a.SetRotation(q)
-		g.AddActor(a)
+func (g *Game) loadData() {
+	// Use the default controls, unless they've been remapped
+	g.inputMap = NewDefaultInputMap()
+	if !g.headless {
+		g.inputMap.Load("Assets/Controls.json")
 	}
 
-	// Setup lights
-	g.renderer.SetAmbientLight(math.Vector3{X: 0.2, Y: 0.2, Z: 0.2})
-	dir := g.renderer.dirLight
-	dir.Direction = math.Vector3{X: 0.0, Y: -0.7, Z: -0.7}
-	dir.DiffuseColor = math.Vector3{X: 0.78, Y: 0.88, Z: 1.0}
-	dir.SpecColor = math.Vector3{X: 0.8, Y: 0.8, Z: 0.8}
-
-	// Camera actor
-	camera := NewCameraActor(g)
-	g.AddActor(camera)
-
+	// Actors and lights come from the scene file
+	g.LoadScene("Assets/Scene.json")
 }
 
 func (g *Game) unloadData() {
 	// Delete actors
 	for len(g.actors) > 0 {
-		g.actors[0].Destroy()
+		g.DestroyActor(g.actors[0])
 	}
 
 	if g.renderer != nil {
@@ -240,6 +257,11 @@ func (g *Game) unloadData() {
 
 func (g *Game) Shutdown() (err error) {
 	defer sdl.Quit()
+	defer func() {
+		if g.inputSystem != nil {
+			g.inputSystem.Shutdown()
+		}
+	}()
 	defer func() {
 		if g.renderer != nil {
 			err = g.renderer.Shutdown()
@@ -251,6 +273,22 @@ func (g *Game) Shutdown() (err error) {
 	return
 }
 
+func (g *Game) GetInputMap() *InputMap {
+	return g.inputMap
+}
+
+func (g *Game) GetClock() Clock {
+	return g.clock
+}
+
+func (g *Game) IsRunning() bool {
+	return g.isRunning
+}
+
+func (g *Game) GetActors() []Actor {
+	return g.actors
+}
+
 func (g *Game) AddActor(actor Actor) {
 	// If we're updating actors, need to add to pending
 	if g.updatingActors {
@@ -258,9 +296,14 @@ func (g *Game) AddActor(actor Actor) {
 	} else {
 		g.actors = append(g.actors, actor)
 	}
+
+	actor.OnAdded()
 }
 
+// RemoveActor takes the actor out of the game without destroying it.
 func (g *Game) RemoveActor(actor Actor) {
+	count := len(g.pendingActors) + len(g.actors)
+
 	g.pendingActors = slices.DeleteFunc(g.pendingActors, func(a Actor) bool {
 		return a == actor
 	})
@@ -268,8 +311,69 @@ func (g *Game) RemoveActor(actor Actor) {
 	g.actors = slices.DeleteFunc(g.actors, func(a Actor) bool {
 		return a == actor
 	})
+
+	if len(g.pendingActors)+len(g.actors) < count {
+		actor.OnRemoved()
+	}
+}
+
+// DestroyActor removes the actor and all of its components from the game.
+func (g *Game) DestroyActor(actor Actor) {
+	actor.OnDestroy()
+
+	// Detach from the parent, and destroy the children along with the actor
+	if parent := actor.GetParent(); parent != nil {
+		RemoveChild(parent, actor)
+	}
+	for len(actor.GetChildren()) > 0 {
+		g.DestroyActor(actor.GetChildren()[0])
+	}
+
+	// Remove components, highest update order first
+	for components := actor.GetComponents(); len(components) > 0; components = actor.GetComponents() {
+		actor.RemoveComponent(components[len(components)-1])
+	}
+
+	g.RemoveActor(actor)
+}
+
+// addComponent registers a component the game keeps track of, such as a sprite
+func (g *Game) addComponent(c Component) {
+	switch c := c.(type) {
+	case Sprite:
+		g.renderer.AddSprite(c)
+	case MeshComponent:
+		g.renderer.AddMeshComp(c)
+	case BoxComponent:
+		g.physWorld.AddBox(c)
+	}
+}
+
+// removeComponent unregisters a component added with addComponent
+func (g *Game) removeComponent(c Component) {
+	switch c := c.(type) {
+	case Sprite:
+		g.renderer.RemoveSprite(c)
+	case MeshComponent:
+		g.renderer.RemoveMeshComp(c)
+	case BoxComponent:
+		g.physWorld.RemoveBox(c)
+	}
+}
+
+func (g *Game) GetCamera() *CameraActor {
+	return g.camera
+}
+
+// SetCamera sets the camera the view is drawn from
+func (g *Game) SetCamera(camera *CameraActor) {
+	g.camera = camera
 }
 
 func (g *Game) GetRenderer() *Renderer {
 	return g.renderer
 }
+
+func (g *Game) GetPhysWorld() *PhysWorld {
+	return g.physWorld
+}
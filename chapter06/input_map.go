@@ -0,0 +1,300 @@
+package chapter06
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ishtaka/go-game-programming/chapter06/math"
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// Binding is one input that triggers an action.
+// Exactly one of the fields should be set.
+type Binding struct {
+	// Key name, as used by SDL_GetScancodeFromName ("Space", "W", ...)
+	Key string `json:"key,omitempty"`
+	// Mouse button number (sdl.BUTTON_LEFT, ...)
+	MouseButton uint32 `json:"mouseButton,omitempty"`
+	// Controller button name, as used by SDL_GameControllerGetButtonFromString ("a", "start", ...)
+	ControllerButton string `json:"controllerButton,omitempty"`
+
+	scancode sdl.Scancode
+	button   sdl.GameControllerButton
+}
+
+func (b *Binding) resolve() error {
+	switch {
+	case b.Key != "":
+		b.scancode = sdl.GetScancodeFromName(b.Key)
+		if b.scancode == sdl.SCANCODE_UNKNOWN {
+			return fmt.Errorf("unknown key %q", b.Key)
+		}
+	case b.MouseButton != 0:
+	case b.ControllerButton != "":
+		b.button = sdl.GameControllerGetButtonFromString(b.ControllerButton)
+		if b.button == sdl.CONTROLLER_BUTTON_INVALID {
+			return fmt.Errorf("unknown controller button %q", b.ControllerButton)
+		}
+	default:
+		return fmt.Errorf("binding has no key or button")
+	}
+
+	return nil
+}
+
+// values returns whether the binding was down last frame and this frame
+func (b *Binding) values(state *InputState) (prev, curr bool) {
+	switch {
+	case b.Key != "":
+		k := &state.Keyboard
+		return k.prevState[b.scancode] == 1, k.currState[b.scancode] == 1
+	case b.MouseButton != 0:
+		m := &state.Mouse
+		mask := sdl.Button(b.MouseButton)
+		return m.prevButtons&mask != 0, m.currButtons&mask != 0
+	default:
+		c := &state.Controller
+		return c.prevButtons[b.button] == 1, c.currButtons[b.button] == 1
+	}
+}
+
+// AxisBinding is one input that drives an axis in [-1, 1].
+// Either a pair of keys or a controller axis should be set.
+type AxisBinding struct {
+	// Keys for the positive and negative directions
+	PositiveKey string `json:"positiveKey,omitempty"`
+	NegativeKey string `json:"negativeKey,omitempty"`
+	// Controller axis name, as used by SDL_GameControllerGetAxisFromString ("leftx", "righttrigger", ...)
+	ControllerAxis string `json:"controllerAxis,omitempty"`
+	// Flip the direction of the controller axis
+	Invert bool `json:"invert,omitempty"`
+
+	positive sdl.Scancode
+	negative sdl.Scancode
+	axis     sdl.GameControllerAxis
+}
+
+func (b *AxisBinding) resolve() error {
+	if b.ControllerAxis != "" {
+		b.axis = sdl.GameControllerGetAxisFromString(b.ControllerAxis)
+		if b.axis == sdl.CONTROLLER_AXIS_INVALID {
+			return fmt.Errorf("unknown controller axis %q", b.ControllerAxis)
+		}
+		return nil
+	}
+
+	if b.PositiveKey == "" && b.NegativeKey == "" {
+		return fmt.Errorf("axis binding has no keys or controller axis")
+	}
+	if b.PositiveKey != "" {
+		b.positive = sdl.GetScancodeFromName(b.PositiveKey)
+		if b.positive == sdl.SCANCODE_UNKNOWN {
+			return fmt.Errorf("unknown key %q", b.PositiveKey)
+		}
+	}
+	if b.NegativeKey != "" {
+		b.negative = sdl.GetScancodeFromName(b.NegativeKey)
+		if b.negative == sdl.SCANCODE_UNKNOWN {
+			return fmt.Errorf("unknown key %q", b.NegativeKey)
+		}
+	}
+
+	return nil
+}
+
+func (b *AxisBinding) value(state *InputState) float32 {
+	if b.ControllerAxis != "" {
+		c := &state.Controller
+		var value float32
+		switch b.axis {
+		case sdl.CONTROLLER_AXIS_LEFTX:
+			value = c.leftStick.X
+		case sdl.CONTROLLER_AXIS_LEFTY:
+			value = c.leftStick.Y
+		case sdl.CONTROLLER_AXIS_RIGHTX:
+			value = c.rightStick.X
+		case sdl.CONTROLLER_AXIS_RIGHTY:
+			value = c.rightStick.Y
+		case sdl.CONTROLLER_AXIS_TRIGGERLEFT:
+			value = c.leftTrigger
+		case sdl.CONTROLLER_AXIS_TRIGGERRIGHT:
+			value = c.rightTrigger
+		}
+		if b.Invert {
+			value = -value
+		}
+		return value
+	}
+
+	var value float32
+	if b.PositiveKey != "" && state.Keyboard.GetKeyValue(b.positive) {
+		value += 1.0
+	}
+	if b.NegativeKey != "" && state.Keyboard.GetKeyValue(b.negative) {
+		value -= 1.0
+	}
+
+	return value
+}
+
+// InputMap maps named actions and axes to the inputs bound to them.
+type InputMap struct {
+	actions map[string][]Binding
+	axes    map[string][]AxisBinding
+}
+
+// inputMapData is the JSON layout of an InputMap
+type inputMapData struct {
+	Actions map[string][]Binding     `json:"actions,omitempty"`
+	Axes    map[string][]AxisBinding `json:"axes,omitempty"`
+}
+
+func NewInputMap() *InputMap {
+	return &InputMap{
+		actions: make(map[string][]Binding),
+		axes:    make(map[string][]AxisBinding),
+	}
+}
+
+// NewDefaultInputMap creates the controls used when no bindings are loaded.
+func NewDefaultInputMap() *InputMap {
+	m := NewInputMap()
+	_ = m.BindAxis("Forward",
+		AxisBinding{PositiveKey: "W", NegativeKey: "S"},
+		AxisBinding{ControllerAxis: "lefty"},
+	)
+	_ = m.BindAxis("Turn",
+		AxisBinding{PositiveKey: "D", NegativeKey: "A"},
+		AxisBinding{ControllerAxis: "leftx"},
+	)
+
+	return m
+}
+
+// Load reads bindings from a JSON file.
+// Actions and axes in the file replace the ones already bound.
+func (m *InputMap) Load(fileName string) bool {
+	file, err := assets.ReadFile(fileName)
+	if err != nil {
+		sdl.Log("file not found: InputMap %s %s", fileName, err)
+		return false
+	}
+
+	if err := json.Unmarshal(file, m); err != nil {
+		sdl.Log("input map %s is not valid: %s", fileName, err)
+		return false
+	}
+
+	return true
+}
+
+func (m *InputMap) MarshalJSON() ([]byte, error) {
+	return json.Marshal(inputMapData{Actions: m.actions, Axes: m.axes})
+}
+
+func (m *InputMap) UnmarshalJSON(data []byte) error {
+	var doc inputMapData
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+
+	// Check every binding before changing any
+	next := NewInputMap()
+	for name, bindings := range doc.Actions {
+		if err := next.BindAction(name, bindings...); err != nil {
+			return err
+		}
+	}
+	for name, bindings := range doc.Axes {
+		if err := next.BindAxis(name, bindings...); err != nil {
+			return err
+		}
+	}
+
+	if m.actions == nil {
+		m.actions = make(map[string][]Binding)
+	}
+	if m.axes == nil {
+		m.axes = make(map[string][]AxisBinding)
+	}
+	for name, bindings := range next.actions {
+		m.actions[name] = bindings
+	}
+	for name, bindings := range next.axes {
+		m.axes[name] = bindings
+	}
+
+	return nil
+}
+
+// BindAction replaces the bindings of an action.
+// With no bindings the action is unbound.
+func (m *InputMap) BindAction(action string, bindings ...Binding) error {
+	resolved := make([]Binding, len(bindings))
+	for i, b := range bindings {
+		if err := b.resolve(); err != nil {
+			return fmt.Errorf("action %s: %w", action, err)
+		}
+		resolved[i] = b
+	}
+
+	m.actions[action] = resolved
+	return nil
+}
+
+// BindAxis replaces the bindings of an axis.
+// With no bindings the axis is unbound.
+func (m *InputMap) BindAxis(axis string, bindings ...AxisBinding) error {
+	resolved := make([]AxisBinding, len(bindings))
+	for i, b := range bindings {
+		if err := b.resolve(); err != nil {
+			return fmt.Errorf("axis %s: %w", axis, err)
+		}
+		resolved[i] = b
+	}
+
+	m.axes[axis] = resolved
+	return nil
+}
+
+func (m *InputMap) GetActionBindings(action string) []Binding {
+	return m.actions[action]
+}
+
+func (m *InputMap) GetAxisBindings(axis string) []AxisBinding {
+	return m.axes[axis]
+}
+
+// GetActionValue reports whether any input bound to the action is down.
+func (m *InputMap) GetActionValue(state *InputState, action string) bool {
+	for _, b := range m.actions[action] {
+		if _, curr := b.values(state); curr {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetActionState compares the action against the previous frame,
+// treating it as down while any of its inputs are down.
+func (m *InputMap) GetActionState(state *InputState, action string) ButtonState {
+	var prev, curr bool
+	for _, b := range m.actions[action] {
+		p, c := b.values(state)
+		prev = prev || p
+		curr = curr || c
+	}
+
+	return buttonState(prev, curr)
+}
+
+// GetAxisValue sums the inputs bound to the axis, clamped to [-1, 1].
+func (m *InputMap) GetAxisValue(state *InputState, axis string) float32 {
+	var value float32
+	for _, b := range m.axes[axis] {
+		value += b.value(state)
+	}
+
+	return math.Clamp(value, -1.0, 1.0)
+}
@@ -0,0 +1,685 @@
+package math
+
+// gjkMaxIterations is how many times GJK refines its simplex before giving up.
+// It only runs out when the shapes are just touching, and then they're
+// reported as not colliding, since GJK never found the origin inside.
+const gjkMaxIterations = 32
+
+// Shape is a 3D shape. Two shapes can be tested with Intersect,
+// and a line segment can be cast against a shape with SegmentCast.
+type Shape interface {
+	// Contains reports whether p is inside the shape or on its surface
+	Contains(p Vector3) bool
+}
+
+// convexShape is a shape GJK can test, given its furthest point in any direction
+type convexShape interface {
+	Shape
+	support(d Vector3) Vector3
+}
+
+// LineSegment is the line between two points.
+type LineSegment struct {
+	Start, End Vector3
+}
+
+// PointOnSegment returns the point at t along the segment (0 is Start, 1 is End).
+func (l LineSegment) PointOnSegment(t float32) Vector3 {
+	return l.Start.Lerp(l.End, t)
+}
+
+// ClosestPoint returns the point on the segment closest to p.
+func (l LineSegment) ClosestPoint(p Vector3) Vector3 {
+	ab := l.End.Sub(l.Start)
+	lenSq := ab.LengthSq()
+	if lenSq == 0 {
+		return l.Start
+	}
+
+	t := Clamp(p.Sub(l.Start).Dot(ab)/lenSq, 0, 1)
+	return l.PointOnSegment(t)
+}
+
+// MinDistSq returns the squared distance from p to the segment.
+func (l LineSegment) MinDistSq(p Vector3) float32 {
+	return l.ClosestPoint(p).Sub(p).LengthSq()
+}
+
+// Plane is the plane Dot(Normal, p) = D. Points behind it (opposite the normal)
+// count as inside, so a shape intersects the plane if any of it is on or behind it.
+type Plane struct {
+	Normal Vector3
+	D      float32
+}
+
+// NewPlaneFromPoints returns the plane through a, b and c, facing
+// the way a, b, c go counter-clockwise.
+func NewPlaneFromPoints(a, b, c Vector3) Plane {
+	normal := b.Sub(a).Cross(c.Sub(a)).Normalize()
+	return Plane{Normal: normal, D: a.Dot(normal)}
+}
+
+// SignedDist returns how far p is in front of the plane (negative if it's behind).
+func (p Plane) SignedDist(point Vector3) float32 {
+	return point.Dot(p.Normal) - p.D
+}
+
+func (p Plane) Contains(point Vector3) bool {
+	return p.SignedDist(point) <= 0
+}
+
+// Sphere is a sphere around a center point.
+type Sphere struct {
+	Center Vector3
+	Radius float32
+}
+
+func (s Sphere) Contains(p Vector3) bool {
+	return p.Sub(s.Center).LengthSq() <= s.Radius*s.Radius
+}
+
+func (s Sphere) support(d Vector3) Vector3 {
+	return s.Center.Add(d.Normalize().MulScalar(s.Radius))
+}
+
+// AABB is an axis-aligned bounding box.
+type AABB struct {
+	Min, Max Vector3
+}
+
+// UpdateMinMax grows the box to contain p.
+func (b *AABB) UpdateMinMax(p Vector3) {
+	b.Min = Vector3{Min(b.Min.X, p.X), Min(b.Min.Y, p.Y), Min(b.Min.Z, p.Z)}
+	b.Max = Vector3{Max(b.Max.X, p.X), Max(b.Max.Y, p.Y), Max(b.Max.Z, p.Z)}
+}
+
+// Transform returns the smallest box containing this box transformed by mat.
+func (b AABB) Transform(mat Matrix4) AABB {
+	corners := b.corners()
+	result := AABB{Min: corners[0].Transform(mat, 1), Max: corners[0].Transform(mat, 1)}
+	for _, c := range corners[1:] {
+		result.UpdateMinMax(c.Transform(mat, 1))
+	}
+
+	return result
+}
+
+// ClosestPoint returns the point of the box closest to p (p itself if it's inside).
+func (b AABB) ClosestPoint(p Vector3) Vector3 {
+	return Vector3{
+		X: Clamp(p.X, b.Min.X, b.Max.X),
+		Y: Clamp(p.Y, b.Min.Y, b.Max.Y),
+		Z: Clamp(p.Z, b.Min.Z, b.Max.Z),
+	}
+}
+
+// MinDistSq returns the squared distance from p to the box (0 if it's inside).
+func (b AABB) MinDistSq(p Vector3) float32 {
+	return b.ClosestPoint(p).Sub(p).LengthSq()
+}
+
+func (b AABB) Contains(p Vector3) bool {
+	return p.X >= b.Min.X && p.X <= b.Max.X &&
+		p.Y >= b.Min.Y && p.Y <= b.Max.Y &&
+		p.Z >= b.Min.Z && p.Z <= b.Max.Z
+}
+
+func (b AABB) corners() [8]Vector3 {
+	return [8]Vector3{
+		{b.Min.X, b.Min.Y, b.Min.Z},
+		{b.Max.X, b.Min.Y, b.Min.Z},
+		{b.Min.X, b.Max.Y, b.Min.Z},
+		{b.Min.X, b.Min.Y, b.Max.Z},
+		{b.Max.X, b.Max.Y, b.Min.Z},
+		{b.Max.X, b.Min.Y, b.Max.Z},
+		{b.Min.X, b.Max.Y, b.Max.Z},
+		{b.Max.X, b.Max.Y, b.Max.Z},
+	}
+}
+
+func (b AABB) support(d Vector3) Vector3 {
+	p := b.Min
+	if d.X > 0 {
+		p.X = b.Max.X
+	}
+	if d.Y > 0 {
+		p.Y = b.Max.Y
+	}
+	if d.Z > 0 {
+		p.Z = b.Max.Z
+	}
+
+	return p
+}
+
+// OBB is a box rotated about its center. The zero rotation is no rotation.
+type OBB struct {
+	Center   Vector3
+	Rotation Quaternion
+	// Half the size of the box along each axis
+	Extents Vector3
+}
+
+// toLocal returns p relative to the box's center and axes
+func (b OBB) toLocal(p Vector3) Vector3 {
+	return b.toLocalDir(p.Sub(b.Center))
+}
+
+// toLocalDir returns the direction d relative to the box's axes
+func (b OBB) toLocalDir(d Vector3) Vector3 {
+	inverse := b.Rotation
+	inverse.Conjugate()
+	return d.TransformByQuaternion(&inverse)
+}
+
+func (b OBB) Contains(p Vector3) bool {
+	local := b.toLocal(p)
+	return Abs(local.X) <= b.Extents.X && Abs(local.Y) <= b.Extents.Y && Abs(local.Z) <= b.Extents.Z
+}
+
+func (b OBB) support(d Vector3) Vector3 {
+	local := AABB{Min: b.Extents.MulScalar(-1), Max: b.Extents}.support(b.toLocalDir(d))
+	return b.Center.Add(local.TransformByQuaternion(&b.Rotation))
+}
+
+// Capsule is a line segment with a radius, like a pill.
+type Capsule struct {
+	Segment LineSegment
+	Radius  float32
+}
+
+// PointOnSegment returns the point at t along the capsule's segment.
+func (c Capsule) PointOnSegment(t float32) Vector3 {
+	return c.Segment.PointOnSegment(t)
+}
+
+func (c Capsule) Contains(p Vector3) bool {
+	return c.Segment.MinDistSq(p) <= c.Radius*c.Radius
+}
+
+func (c Capsule) support(d Vector3) Vector3 {
+	end := c.Segment.Start
+	if c.Segment.End.Dot(d) > end.Dot(d) {
+		end = c.Segment.End
+	}
+
+	return end.Add(d.Normalize().MulScalar(c.Radius))
+}
+
+// Triangle is a flat triangle, facing the way A, B, C go counter-clockwise.
+type Triangle struct {
+	A, B, C Vector3
+}
+
+// Normal returns the direction the triangle faces.
+func (t Triangle) Normal() Vector3 {
+	return t.B.Sub(t.A).Cross(t.C.Sub(t.A)).Normalize()
+}
+
+func (t Triangle) Contains(p Vector3) bool {
+	n := t.B.Sub(t.A).Cross(t.C.Sub(t.A))
+	if n.LengthSq() == 0 || !NearZero(p.Sub(t.A).Dot(n.Normalize())) {
+		return false
+	}
+
+	// In the triangle's plane, so inside if it's on the inner side of every edge
+	return t.B.Sub(t.A).Cross(p.Sub(t.A)).Dot(n) >= 0 &&
+		t.C.Sub(t.B).Cross(p.Sub(t.B)).Dot(n) >= 0 &&
+		t.A.Sub(t.C).Cross(p.Sub(t.C)).Dot(n) >= 0
+}
+
+func (t Triangle) support(d Vector3) Vector3 {
+	p := t.A
+	if t.B.Dot(d) > p.Dot(d) {
+		p = t.B
+	}
+	if t.C.Dot(d) > p.Dot(d) {
+		p = t.C
+	}
+
+	return p
+}
+
+// TriangleMesh is the surface made of a set of triangles. It doesn't need to be
+// convex or closed, and only its surface counts as inside.
+type TriangleMesh struct {
+	Triangles []Triangle
+}
+
+// NewTriangleMesh creates a mesh from vertex positions and three indices per triangle.
+func NewTriangleMesh(vertices []Vector3, indices []uint32) TriangleMesh {
+	m := TriangleMesh{Triangles: make([]Triangle, 0, len(indices)/3)}
+	for i := 0; i+2 < len(indices); i += 3 {
+		m.Triangles = append(m.Triangles, Triangle{
+			A: vertices[indices[i]],
+			B: vertices[indices[i+1]],
+			C: vertices[indices[i+2]],
+		})
+	}
+
+	return m
+}
+
+// Transform returns the mesh with every vertex transformed by mat.
+func (m TriangleMesh) Transform(mat Matrix4) TriangleMesh {
+	result := TriangleMesh{Triangles: make([]Triangle, len(m.Triangles))}
+	for i, t := range m.Triangles {
+		result.Triangles[i] = Triangle{
+			A: t.A.Transform(mat, 1),
+			B: t.B.Transform(mat, 1),
+			C: t.C.Transform(mat, 1),
+		}
+	}
+
+	return result
+}
+
+// GetBounds returns the smallest box containing the mesh.
+func (m TriangleMesh) GetBounds() AABB {
+	if len(m.Triangles) == 0 {
+		return AABB{}
+	}
+
+	box := AABB{Min: m.Triangles[0].A, Max: m.Triangles[0].A}
+	for _, t := range m.Triangles {
+		box.UpdateMinMax(t.A)
+		box.UpdateMinMax(t.B)
+		box.UpdateMinMax(t.C)
+	}
+
+	return box
+}
+
+func (m TriangleMesh) Contains(p Vector3) bool {
+	for _, t := range m.Triangles {
+		if t.Contains(p) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Intersect reports whether two shapes overlap (touching counts).
+func Intersect(a, b Shape) bool {
+	// Planes and meshes aren't convex, so they're handled separately
+	if p, ok := a.(Plane); ok {
+		return intersectPlane(p, b)
+	}
+	if p, ok := b.(Plane); ok {
+		return intersectPlane(p, a)
+	}
+	if m, ok := a.(TriangleMesh); ok {
+		return intersectMesh(m, b)
+	}
+	if m, ok := b.(TriangleMesh); ok {
+		return intersectMesh(m, a)
+	}
+
+	// Quicker tests for the common cases
+	switch a := a.(type) {
+	case Sphere:
+		switch b := b.(type) {
+		case Sphere:
+			radii := a.Radius + b.Radius
+			return a.Center.Sub(b.Center).LengthSq() <= radii*radii
+		case AABB:
+			return b.MinDistSq(a.Center) <= a.Radius*a.Radius
+		}
+	case AABB:
+		switch b := b.(type) {
+		case AABB:
+			return a.Min.X <= b.Max.X && a.Max.X >= b.Min.X &&
+				a.Min.Y <= b.Max.Y && a.Max.Y >= b.Min.Y &&
+				a.Min.Z <= b.Max.Z && a.Max.Z >= b.Min.Z
+		case Sphere:
+			return a.MinDistSq(b.Center) <= b.Radius*b.Radius
+		}
+	}
+
+	ca, okA := a.(convexShape)
+	cb, okB := b.(convexShape)
+	if !okA || !okB {
+		return false
+	}
+
+	return gjk(ca, cb)
+}
+
+func intersectPlane(p Plane, s Shape) bool {
+	switch s := s.(type) {
+	case Plane:
+		// Two planes facing opposite ways only overlap if the gap between them is behind both
+		if NearZero(p.Normal.Dot(s.Normal) + 1) {
+			return -s.D <= p.D
+		}
+		return true
+	case TriangleMesh:
+		for _, t := range s.Triangles {
+			if intersectPlane(p, t) {
+				return true
+			}
+		}
+		return false
+	case convexShape:
+		// Check the point of the shape furthest behind the plane
+		return p.Contains(s.support(p.Normal.MulScalar(-1)))
+	}
+
+	return false
+}
+
+func intersectMesh(m TriangleMesh, s Shape) bool {
+	for _, t := range m.Triangles {
+		if Intersect(t, s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// gjk reports whether two convex shapes overlap, by checking if their
+// Minkowski difference (every point of a minus every point of b) contains the origin
+func gjk(a, b convexShape) bool {
+	support := func(d Vector3) Vector3 {
+		return a.support(d).Sub(b.support(d.MulScalar(-1)))
+	}
+
+	// The simplex is kept with the newest point first
+	simplex := []Vector3{support(Vector3UnitX)}
+	d := simplex[0].MulScalar(-1)
+	for range gjkMaxIterations {
+		if d.LengthSq() == 0 {
+			// The origin is on the simplex
+			return true
+		}
+
+		p := support(d)
+		if p.Dot(d) < 0 {
+			// Nothing reaches past the origin in this direction
+			return false
+		}
+
+		simplex = append([]Vector3{p}, simplex...)
+		var contains bool
+		simplex, d, contains = nextSimplex(simplex)
+		if contains {
+			return true
+		}
+	}
+
+	// Never converged, so don't report a collision that may not be there
+	return false
+}
+
+// nextSimplex reduces the simplex to the part nearest the origin,
+// and returns the direction of the origin from it
+func nextSimplex(s []Vector3) ([]Vector3, Vector3, bool) {
+	switch len(s) {
+	case 2:
+		return simplexLine(s[0], s[1])
+	case 3:
+		return simplexTriangle(s[0], s[1], s[2])
+	default:
+		a, b, c, d := s[0], s[1], s[2], s[3]
+		ab, ac, ad, ao := b.Sub(a), c.Sub(a), d.Sub(a), a.MulScalar(-1)
+
+		if ab.Cross(ac).Dot(ao) > 0 {
+			return simplexTriangle(a, b, c)
+		}
+		if ac.Cross(ad).Dot(ao) > 0 {
+			return simplexTriangle(a, c, d)
+		}
+		if ad.Cross(ab).Dot(ao) > 0 {
+			return simplexTriangle(a, d, b)
+		}
+
+		return s, Vector3Zero, true
+	}
+}
+
+func simplexLine(a, b Vector3) ([]Vector3, Vector3, bool) {
+	ab, ao := b.Sub(a), a.MulScalar(-1)
+	if ab.Dot(ao) > 0 {
+		return []Vector3{a, b}, ab.Cross(ao).Cross(ab), false
+	}
+
+	return []Vector3{a}, ao, false
+}
+
+func simplexTriangle(a, b, c Vector3) ([]Vector3, Vector3, bool) {
+	ab, ac, ao := b.Sub(a), c.Sub(a), a.MulScalar(-1)
+	abc := ab.Cross(ac)
+
+	if abc.Cross(ac).Dot(ao) > 0 {
+		if ac.Dot(ao) > 0 {
+			return []Vector3{a, c}, ac.Cross(ao).Cross(ac), false
+		}
+		return simplexLine(a, b)
+	}
+	if ab.Cross(abc).Dot(ao) > 0 {
+		return simplexLine(a, b)
+	}
+
+	// The origin is above or below the triangle
+	if abc.Dot(ao) > 0 {
+		return []Vector3{a, b, c}, abc, false
+	}
+
+	return []Vector3{a, c, b}, abc.MulScalar(-1), false
+}
+
+// CastHit is where a segment cast first hits a shape.
+type CastHit struct {
+	// Fraction of the way along the segment
+	T      float32
+	Point  Vector3
+	Normal Vector3
+}
+
+// SegmentCast finds where a line segment, going from Start to End, first hits a shape.
+// A segment starting inside the shape hits at Start, with the normal facing back along it.
+func SegmentCast(l LineSegment, shape Shape) (CastHit, bool) {
+	dir := l.End.Sub(l.Start)
+	if dir.LengthSq() == 0 {
+		return CastHit{}, false
+	}
+
+	if shape.Contains(l.Start) {
+		return CastHit{T: 0, Point: l.Start, Normal: dir.MulScalar(-1).Normalize()}, true
+	}
+
+	var t float32
+	var normal Vector3
+	var ok bool
+	switch s := shape.(type) {
+	case Sphere:
+		t, normal, ok = castSphere(l.Start, dir, s)
+	case Plane:
+		t, normal, ok = castPlane(l.Start, dir, s)
+	case AABB:
+		t, normal, ok = castAABB(l.Start, dir, s)
+	case OBB:
+		t, normal, ok = castOBB(l.Start, dir, s)
+	case Capsule:
+		t, normal, ok = castCapsule(l.Start, dir, s)
+	case Triangle:
+		t, normal, ok = castTriangle(l.Start, dir, s)
+	case TriangleMesh:
+		t = float32(Infinity)
+		for _, tri := range s.Triangles {
+			if triT, triNormal, triOK := castTriangle(l.Start, dir, tri); triOK && triT < t {
+				t, normal, ok = triT, triNormal, true
+			}
+		}
+	}
+	if !ok || t > 1 {
+		return CastHit{}, false
+	}
+
+	return CastHit{T: t, Point: l.PointOnSegment(t), Normal: normal}, true
+}
+
+func castSphere(start, dir Vector3, s Sphere) (float32, Vector3, bool) {
+	// Solve |start + t*dir - center| = radius for the smaller t
+	x := start.Sub(s.Center)
+	a := dir.Dot(dir)
+	b := 2 * x.Dot(dir)
+	c := x.Dot(x) - s.Radius*s.Radius
+	disc := b*b - 4*a*c
+	if disc < 0 {
+		return 0, Vector3{}, false
+	}
+
+	t := (-b - Sqrt(disc)) / (2 * a)
+	if t < 0 {
+		return 0, Vector3{}, false
+	}
+
+	normal := start.Add(dir.MulScalar(t)).Sub(s.Center).Normalize()
+	return t, normal, true
+}
+
+func castPlane(start, dir Vector3, p Plane) (float32, Vector3, bool) {
+	// The start is in front, so it has to be heading behind the plane
+	rate := dir.Dot(p.Normal)
+	if rate >= 0 {
+		return 0, Vector3{}, false
+	}
+
+	return -p.SignedDist(start) / rate, p.Normal, true
+}
+
+func castAABB(start, dir Vector3, b AABB) (float32, Vector3, bool) {
+	// Clip the segment to the slab between each pair of sides
+	starts := [3]float32{start.X, start.Y, start.Z}
+	dirs := [3]float32{dir.X, dir.Y, dir.Z}
+	mins := [3]float32{b.Min.X, b.Min.Y, b.Min.Z}
+	maxs := [3]float32{b.Max.X, b.Max.Y, b.Max.Z}
+	axes := [3]Vector3{Vector3UnitX, Vector3UnitY, Vector3UnitZ}
+
+	tEnter, tExit := float32(0), float32(Infinity)
+	var normal Vector3
+	for i := range 3 {
+		if dirs[i] == 0 {
+			if starts[i] < mins[i] || starts[i] > maxs[i] {
+				return 0, Vector3{}, false
+			}
+			continue
+		}
+
+		t1 := (mins[i] - starts[i]) / dirs[i]
+		t2 := (maxs[i] - starts[i]) / dirs[i]
+		n := axes[i].MulScalar(-1)
+		if t1 > t2 {
+			t1, t2 = t2, t1
+			n = axes[i]
+		}
+
+		if t1 > tEnter {
+			tEnter = t1
+			normal = n
+		}
+		tExit = Min(tExit, t2)
+		if tEnter > tExit {
+			return 0, Vector3{}, false
+		}
+	}
+
+	return tEnter, normal, true
+}
+
+func castOBB(start, dir Vector3, b OBB) (float32, Vector3, bool) {
+	// Cast in the box's space, where it's an AABB
+	localStart := b.toLocal(start)
+	localDir := b.toLocalDir(dir)
+	t, normal, ok := castAABB(localStart, localDir, AABB{Min: b.Extents.MulScalar(-1), Max: b.Extents})
+	if !ok {
+		return 0, Vector3{}, false
+	}
+
+	return t, normal.TransformByQuaternion(&b.Rotation), true
+}
+
+func castCapsule(start, dir Vector3, c Capsule) (float32, Vector3, bool) {
+	// The capsule is a cylinder with a sphere on each end,
+	// so the first hit is the earliest hit on any of them
+	t := float32(Infinity)
+	var normal Vector3
+	var ok bool
+	for _, end := range []Vector3{c.Segment.Start, c.Segment.End} {
+		if endT, endNormal, endOK := castSphere(start, dir, Sphere{end, c.Radius}); endOK && endT < t {
+			t, normal, ok = endT, endNormal, true
+		}
+	}
+
+	axis := c.Segment.End.Sub(c.Segment.Start)
+	if axis.LengthSq() == 0 {
+		return t, normal, ok
+	}
+	axis = axis.Normalize()
+
+	// Solve for the distance from the axis being the radius, ignoring movement along the axis
+	m := start.Sub(c.Segment.Start)
+	mPerp := m.Sub(axis.MulScalar(m.Dot(axis)))
+	dPerp := dir.Sub(axis.MulScalar(dir.Dot(axis)))
+	a := dPerp.Dot(dPerp)
+	b := 2 * mPerp.Dot(dPerp)
+	cc := mPerp.Dot(mPerp) - c.Radius*c.Radius
+	disc := b*b - 4*a*cc
+	if a == 0 || disc < 0 {
+		return t, normal, ok
+	}
+
+	sideT := (-b - Sqrt(disc)) / (2 * a)
+	if sideT < 0 || sideT >= t {
+		return t, normal, ok
+	}
+
+	// Only counts if it's between the ends
+	hit := start.Add(dir.MulScalar(sideT))
+	along := hit.Sub(c.Segment.Start).Dot(axis)
+	if along < 0 || along*along > c.Segment.End.Sub(c.Segment.Start).LengthSq() {
+		return t, normal, ok
+	}
+
+	return sideT, mPerp.Add(dPerp.MulScalar(sideT)).Normalize(), true
+}
+
+func castTriangle(start, dir Vector3, tri Triangle) (float32, Vector3, bool) {
+	// Solve start + t*dir = A + u*(B-A) + v*(C-A) (Moller-Trumbore)
+	e1, e2 := tri.B.Sub(tri.A), tri.C.Sub(tri.A)
+	p := dir.Cross(e2)
+	det := e1.Dot(p)
+	if det == 0 {
+		// Parallel to the triangle
+		return 0, Vector3{}, false
+	}
+
+	inv := 1 / det
+	tv := start.Sub(tri.A)
+	u := tv.Dot(p) * inv
+	if u < 0 || u > 1 {
+		return 0, Vector3{}, false
+	}
+
+	q := tv.Cross(e1)
+	v := dir.Dot(q) * inv
+	if v < 0 || u+v > 1 {
+		return 0, Vector3{}, false
+	}
+
+	t := e2.Dot(q) * inv
+	if t < 0 {
+		return 0, Vector3{}, false
+	}
+
+	// The side of the triangle that faces the segment
+	normal := e1.Cross(e2).Normalize()
+	if normal.Dot(dir) > 0 {
+		normal = normal.MulScalar(-1)
+	}
+
+	return t, normal, true
+}
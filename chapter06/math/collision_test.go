@@ -0,0 +1,121 @@
+package math
+
+import "testing"
+
+func nearlyEqual(a, b Vector3) bool {
+	return NearZero(a.Sub(b).LengthSq())
+}
+
+func TestIntersect(t *testing.T) {
+	box := AABB{Min: Vector3{0, 0, 0}, Max: Vector3{10, 10, 10}}
+	// A cube turned 45 degrees about z, so its corners reach about 7.07 from its center
+	turned := OBB{Center: Vector3{20, 5, 5}, Rotation: *NewQuaternionFromVec(Vector3UnitZ, Pi/4), Extents: Vector3{5, 5, 5}}
+	capsule := Capsule{Segment: LineSegment{Vector3{0, 20, 0}, Vector3{0, 20, 10}}, Radius: 2}
+	floor := Plane{Normal: Vector3UnitZ, D: -1}
+	tri := Triangle{Vector3{-5, -5, 0}, Vector3{5, -5, 0}, Vector3{0, 5, 0}}
+	mesh := TriangleMesh{Triangles: []Triangle{tri, {Vector3{30, 30, 0}, Vector3{31, 30, 0}, Vector3{30, 31, 0}}}}
+
+	tests := []struct {
+		name string
+		a, b Shape
+		want bool
+	}{
+		{"sphere sphere", Sphere{Vector3{0, 0, 0}, 5}, Sphere{Vector3{0, 9, 0}, 5}, true},
+		{"sphere sphere apart", Sphere{Vector3{0, 0, 0}, 5}, Sphere{Vector3{0, 11, 0}, 5}, false},
+		{"sphere box", Sphere{Vector3{13, 5, 5}, 4}, box, true},
+		{"sphere box corner", Sphere{Vector3{13, 13, 13}, 4}, box, false},
+		{"box box", box, AABB{Vector3{10, 10, 10}, Vector3{20, 20, 20}}, true},
+		{"box box apart", box, AABB{Vector3{5, 11, 5}, Vector3{20, 20, 20}}, false},
+		{"box obb", box, turned, false},
+		{"box obb overlap", AABB{Vector3{0, 0, 0}, Vector3{13.5, 10, 10}}, turned, true},
+		{"sphere obb", Sphere{Vector3{11, 5, 5}, 1.5}, turned, false},
+		{"sphere obb touching", Sphere{Vector3{12, 5, 5}, 1.5}, turned, true},
+		{"capsule capsule", capsule, Capsule{LineSegment{Vector3{-5, 23, 5}, Vector3{5, 23, 5}}, 1.5}, true},
+		{"capsule capsule apart", capsule, Capsule{LineSegment{Vector3{-5, 24, 5}, Vector3{5, 24, 5}}, 1.5}, false},
+		{"capsule sphere", capsule, Sphere{Vector3{0, 20, 13}, 1.5}, true},
+		{"capsule box", capsule, box, false},
+		{"capsule box overlap", capsule, AABB{Vector3{-1, 11, 4}, Vector3{1, 18.5, 6}}, true},
+		{"plane sphere", floor, Sphere{Vector3{0, 0, 4}, 5.5}, true},
+		{"plane sphere above", floor, Sphere{Vector3{0, 0, 4}, 2}, false},
+		{"plane box", floor, box, false},
+		{"plane capsule", floor, Capsule{LineSegment{Vector3{0, 0, 5}, Vector3{0, 0, -5}}, 1}, true},
+		{"plane plane facing", floor, Plane{Normal: Vector3{0, 0, -1}, D: 2}, true},
+		{"plane plane apart", floor, Plane{Normal: Vector3{0, 0, -1}, D: 0}, false},
+		{"triangle sphere", tri, Sphere{Vector3{0, 0, 1}, 1.5}, true},
+		{"triangle sphere above", tri, Sphere{Vector3{0, 0, 2}, 1.5}, false},
+		{"triangle box", tri, AABB{Vector3{-1, -1, -1}, Vector3{1, 1, 1}}, true},
+		{"triangle box past edge", tri, AABB{Vector3{4, 1, -1}, Vector3{6, 3, 1}}, false},
+		{"mesh sphere", mesh, Sphere{Vector3{30.2, 30.2, 0.5}, 1}, true},
+		{"mesh obb", mesh, turned, false},
+		{"mesh plane", mesh, floor, false},
+	}
+
+	for _, tt := range tests {
+		if got := Intersect(tt.a, tt.b); got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+		if got := Intersect(tt.b, tt.a); got != tt.want {
+			t.Errorf("%s (swapped): expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestSegmentCast(t *testing.T) {
+	turned := OBB{Center: Vector3{20, 0, 0}, Rotation: *NewQuaternionFromVec(Vector3UnitZ, Pi/4), Extents: Vector3{5, 5, 5}}
+	diag := Vector3{-1, -1, 0}.Normalize()
+
+	tests := []struct {
+		name   string
+		seg    LineSegment
+		shape  Shape
+		want   bool
+		point  Vector3
+		normal Vector3
+	}{
+		{"sphere", LineSegment{Vector3{-10, 0, 0}, Vector3{10, 0, 0}}, Sphere{Vector3{0, 0, 0}, 2}, true, Vector3{-2, 0, 0}, Vector3{-1, 0, 0}},
+		{"sphere short", LineSegment{Vector3{-10, 0, 0}, Vector3{-5, 0, 0}}, Sphere{Vector3{0, 0, 0}, 2}, false, Vector3{}, Vector3{}},
+		{"plane", LineSegment{Vector3{0, 0, 10}, Vector3{0, 0, -10}}, Plane{Normal: Vector3UnitZ, D: 2}, true, Vector3{0, 0, 2}, Vector3UnitZ},
+		{"plane away", LineSegment{Vector3{0, 0, 10}, Vector3{0, 0, 20}}, Plane{Normal: Vector3UnitZ, D: 2}, false, Vector3{}, Vector3{}},
+		{"box", LineSegment{Vector3{5, 20, 5}, Vector3{5, -20, 5}}, AABB{Vector3{0, 0, 0}, Vector3{10, 10, 10}}, true, Vector3{5, 10, 5}, Vector3UnitY},
+		{"box miss", LineSegment{Vector3{11, 20, 5}, Vector3{11, -20, 5}}, AABB{Vector3{0, 0, 0}, Vector3{10, 10, 10}}, false, Vector3{}, Vector3{}},
+		{"obb", LineSegment{Vector3{30, 10, 0}, Vector3{20, 0, 0}}, turned, true, Vector3{20, 0, 0}.Add(diag.MulScalar(-5)), diag.MulScalar(-1)},
+		{"capsule side", LineSegment{Vector3{-10, 0, 5}, Vector3{10, 0, 5}}, Capsule{LineSegment{Vector3{0, 0, 0}, Vector3{0, 0, 10}}, 2}, true, Vector3{-2, 0, 5}, Vector3{-1, 0, 0}},
+		{"capsule end", LineSegment{Vector3{0, 0, 20}, Vector3{0, 0, 0}}, Capsule{LineSegment{Vector3{0, 0, 0}, Vector3{0, 0, 10}}, 2}, true, Vector3{0, 0, 12}, Vector3UnitZ},
+		{"triangle", LineSegment{Vector3{0, 0, -5}, Vector3{0, 0, 5}}, Triangle{Vector3{-5, -5, 0}, Vector3{5, -5, 0}, Vector3{0, 5, 0}}, true, Vector3{0, 0, 0}, Vector3{0, 0, -1}},
+		{"triangle miss", LineSegment{Vector3{5, 5, -5}, Vector3{5, 5, 5}}, Triangle{Vector3{-5, -5, 0}, Vector3{5, -5, 0}, Vector3{0, 5, 0}}, false, Vector3{}, Vector3{}},
+		{"inside", LineSegment{Vector3{5, 5, 5}, Vector3{20, 5, 5}}, AABB{Vector3{0, 0, 0}, Vector3{10, 10, 10}}, true, Vector3{5, 5, 5}, Vector3{-1, 0, 0}},
+	}
+
+	for _, tt := range tests {
+		hit, ok := SegmentCast(tt.seg, tt.shape)
+		if ok != tt.want {
+			t.Errorf("%s: expected hit %v, got %v", tt.name, tt.want, ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if !nearlyEqual(hit.Point, tt.point) {
+			t.Errorf("%s: expected point %v, got %v", tt.name, tt.point, hit.Point)
+		}
+		if !nearlyEqual(hit.Normal, tt.normal) {
+			t.Errorf("%s: expected normal %v, got %v", tt.name, tt.normal, hit.Normal)
+		}
+		if !nearlyEqual(tt.seg.PointOnSegment(hit.T), hit.Point) {
+			t.Errorf("%s: expected t %f to give %v", tt.name, hit.T, hit.Point)
+		}
+	}
+}
+
+func TestSegmentCastMesh(t *testing.T) {
+	// Two triangles, the nearer one second
+	mesh := NewTriangleMesh(
+		[]Vector3{{-5, -5, 0}, {5, -5, 0}, {0, 5, 0}, {-5, -5, 3}, {5, -5, 3}, {0, 5, 3}},
+		[]uint32{0, 1, 2, 3, 4, 5},
+	)
+
+	hit, ok := SegmentCast(LineSegment{Vector3{0, 0, 10}, Vector3{0, 0, -10}}, mesh)
+	if !ok || !nearlyEqual(hit.Point, Vector3{0, 0, 3}) || !nearlyEqual(hit.Normal, Vector3UnitZ) {
+		t.Errorf("expected hit at (0, 0, 3) facing up, got %v %v", ok, hit)
+	}
+}
@@ -39,6 +39,10 @@ func (q *Quaternion) Set(x, y, z, w float32) {
 	q.w = w
 }
 
+func (q *Quaternion) Get() (x, y, z, w float32) {
+	return q.x, q.y, q.z, q.w
+}
+
 func (q *Quaternion) Conjugate() {
 	q.x *= -1.0
 	q.y *= -1.0
@@ -0,0 +1,188 @@
+package rand
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/fnv"
+	"math/rand/v2"
+	"slices"
+
+	"github.com/ishtaka/go-game-programming/chapter06/math"
+)
+
+// Names of the streams used by the game
+const (
+	StreamDefault   = "default"
+	StreamSpawn     = "spawn"
+	StreamAI        = "ai"
+	StreamParticles = "particles"
+)
+
+// RNG is a seeded random number generator split into named streams.
+// Each stream has its own sequence, so drawing numbers for one purpose
+// (like particles) doesn't change the numbers drawn for another (like spawns).
+type RNG struct {
+	seed    uint64
+	streams map[string]*Stream
+}
+
+func NewRNG(seed uint64) *RNG {
+	return &RNG{
+		seed:    seed,
+		streams: make(map[string]*Stream),
+	}
+}
+
+// Seed restarts every stream from a new seed.
+func (r *RNG) Seed(seed uint64) {
+	r.seed = seed
+	for name, s := range r.streams {
+		s.pcg.Seed(seed, streamID(name))
+	}
+}
+
+func (r *RNG) GetSeed() uint64 {
+	return r.seed
+}
+
+// GetStream returns the named stream, starting it if it's new.
+func (r *RNG) GetStream(name string) *Stream {
+	s, ok := r.streams[name]
+	if !ok {
+		s = newStream(r.seed, streamID(name))
+		r.streams[name] = s
+	}
+
+	return s
+}
+
+// MarshalBinary saves the seed and the position of every stream.
+func (r *RNG) MarshalBinary() ([]byte, error) {
+	data := binary.LittleEndian.AppendUint64(nil, r.seed)
+
+	// Sort the names so the same state always gives the same bytes
+	names := make([]string, 0, len(r.streams))
+	for name := range r.streams {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	data = binary.LittleEndian.AppendUint32(data, uint32(len(names)))
+	for _, name := range names {
+		state, err := r.streams[name].MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		data = binary.LittleEndian.AppendUint32(data, uint32(len(name)))
+		data = append(data, name...)
+		data = binary.LittleEndian.AppendUint32(data, uint32(len(state)))
+		data = append(data, state...)
+	}
+
+	return data, nil
+}
+
+// UnmarshalBinary restores a state saved by MarshalBinary.
+func (r *RNG) UnmarshalBinary(data []byte) error {
+	errInvalid := errors.New("rand: invalid RNG state")
+
+	if len(data) < 12 {
+		return errInvalid
+	}
+	seed := binary.LittleEndian.Uint64(data)
+	count := binary.LittleEndian.Uint32(data[8:])
+	data = data[12:]
+
+	// Read a length prefixed field
+	next := func() ([]byte, bool) {
+		if len(data) < 4 {
+			return nil, false
+		}
+		n := binary.LittleEndian.Uint32(data)
+		if uint32(len(data)-4) < n {
+			return nil, false
+		}
+		field := data[4 : 4+n]
+		data = data[4+n:]
+		return field, true
+	}
+
+	streams := make(map[string]*Stream, count)
+	for range count {
+		name, ok := next()
+		if !ok {
+			return errInvalid
+		}
+		state, ok := next()
+		if !ok {
+			return errInvalid
+		}
+
+		s := newStream(seed, streamID(string(name)))
+		if err := s.UnmarshalBinary(state); err != nil {
+			return err
+		}
+		streams[string(name)] = s
+	}
+
+	r.seed = seed
+	r.streams = streams
+	return nil
+}
+
+// streamID gives each stream name its own PCG sequence
+func streamID(name string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(name))
+	return h.Sum64()
+}
+
+// Stream is one sequence of random numbers from an RNG.
+type Stream struct {
+	pcg  *rand.PCG
+	rand *rand.Rand
+}
+
+func newStream(seed, id uint64) *Stream {
+	pcg := rand.NewPCG(seed, id)
+	return &Stream{
+		pcg:  pcg,
+		rand: rand.New(pcg),
+	}
+}
+
+func (s *Stream) GetFloat() float32 {
+	return s.rand.Float32()
+}
+
+func (s *Stream) GetFloatRange(min, max float32) float32 {
+	return min + (max-min)*s.rand.Float32()
+}
+
+func (s *Stream) GetInt() int {
+	return s.rand.Int()
+}
+
+func (s *Stream) GetIntRange(min, max int) int {
+	return min + s.rand.IntN(max-min)
+}
+
+func (s *Stream) GetVector2(min, max math.Vector2) math.Vector2 {
+	v := math.Vector2{X: s.GetFloat(), Y: s.GetFloat()}
+	return min.Add(v.Mul(max.Sub(min)))
+}
+
+func (s *Stream) GetVector3(min, max math.Vector3) math.Vector3 {
+	v := math.Vector3{X: s.GetFloat(), Y: s.GetFloat(), Z: s.GetFloat()}
+	return min.Add(v.Mul(max.Sub(min)))
+}
+
+// MarshalBinary saves the position of the stream.
+func (s *Stream) MarshalBinary() ([]byte, error) {
+	return s.pcg.MarshalBinary()
+}
+
+// UnmarshalBinary restores a position saved by MarshalBinary.
+func (s *Stream) UnmarshalBinary(data []byte) error {
+	return s.pcg.UnmarshalBinary(data)
+}
@@ -18,11 +18,14 @@ type meshData struct {
 }
 
 type Mesh struct {
+	fileName    string
 	textures    []*Texture
 	vertexArray *VertexArray
 	shaderName  string
 	radius      float32
 	specPower   float32
+	// Bounds of the vertices in object space
+	box math.AABB
 }
 
 func (m *Mesh) Load(fileName string, renderer *Renderer) bool {
@@ -43,6 +46,7 @@ func (m *Mesh) Load(fileName string, renderer *Renderer) bool {
 		return false
 	}
 
+	m.fileName = fileName
 	m.shaderName = doc.Shader
 
 	// Skip the vertex format/shader for now
@@ -90,6 +94,11 @@ func (m *Mesh) Load(fileName string, renderer *Renderer) bool {
 
 		pos := math.Vector3{X: vert[0], Y: vert[1], Z: vert[2]}
 		m.radius = math.Max(m.radius, pos.LengthSq())
+		if i == 0 {
+			m.box = math.AABB{Min: pos, Max: pos}
+		} else {
+			m.box.UpdateMinMax(pos)
+		}
 
 		// Add the floats
 		for j := 0; j < vertSize; j++ {
@@ -120,7 +129,10 @@ func (m *Mesh) Load(fileName string, renderer *Renderer) bool {
 		indices = append(indices, ind[2])
 	}
 
-	m.vertexArray = NewVertexArray(vertices, len(vertices), indices, len(indices))
+	// Without an OpenGL context only the mesh data is kept
+	if !renderer.IsHeadless() {
+		m.vertexArray = NewVertexArray(vertices, len(vertices), indices, len(indices))
+	}
 
 	return true
 }
@@ -143,6 +155,10 @@ func (m *Mesh) GetTexture(index int) *Texture {
 	return nil
 }
 
+func (m *Mesh) FileName() string {
+	return m.fileName
+}
+
 func (m *Mesh) ShaderName() string {
 	return m.shaderName
 }
@@ -151,6 +167,10 @@ func (m *Mesh) Radius() float32 {
 	return m.radius
 }
 
+func (m *Mesh) GetBox() math.AABB {
+	return m.box
+}
+
 func (m *Mesh) SpecPower() float32 {
 	return m.specPower
 }
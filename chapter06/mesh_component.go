@@ -4,7 +4,8 @@ import "github.com/go-gl/gl/v3.3-core/gl"
 
 type MeshComponent interface {
 	Component
-	Draw(shader *Shader)
+	Draw(shader *Shader, alpha float32)
+	GetMesh() *Mesh
 	SetMesh(mesh *Mesh)
 	SetTextureIndex(index int)
 }
@@ -14,7 +15,6 @@ func NewMeshComponent(owner Actor, updateOrder int) MeshComponent {
 	mc := &meshComponent{
 		Component: c,
 	}
-	owner.GetGame().GetRenderer().AddMeshComp(mc)
 
 	return mc
 }
@@ -25,10 +25,10 @@ type meshComponent struct {
 	textureIndex int
 }
 
-func (m *meshComponent) Draw(shader *Shader) {
+func (m *meshComponent) Draw(shader *Shader, alpha float32) {
 	if m.mesh != nil {
-		// Set the world transform
-		worldTrans := m.GetOwner().GetWorldTransform()
+		// Set the world transform (between the owner's last two updates)
+		worldTrans := m.GetOwner().GetInterpolatedWorldTransform(alpha)
 		shader.SetMatrixUniform("uWorldTransform", &worldTrans)
 		// Set specular power
 		shader.SetFloatUniform("uSpecPower", m.mesh.SpecPower())
@@ -45,6 +45,10 @@ func (m *meshComponent) Draw(shader *Shader) {
 	}
 }
 
+func (m *meshComponent) GetMesh() *Mesh {
+	return m.mesh
+}
+
 func (m *meshComponent) SetMesh(mesh *Mesh) {
 	m.mesh = mesh
 }
@@ -53,8 +57,21 @@ func (m *meshComponent) SetTextureIndex(index int) {
 	m.textureIndex = index
 }
 
-func (m *meshComponent) Destroy() {
-	owner := m.GetOwner()
-	owner.GetGame().GetRenderer().RemoveMeshComp(m)
-	owner.RemoveComponent(m)
+func (m *meshComponent) GetTypeName() string {
+	return "MeshComponent"
+}
+
+func (m *meshComponent) LoadProperties(props Properties) {
+	var fileName string
+	if props.GetString("meshFile", &fileName) {
+		m.mesh = m.GetOwner().GetGame().GetRenderer().GetMesh(fileName)
+	}
+	props.GetInt("textureIndex", &m.textureIndex)
+}
+
+func (m *meshComponent) SaveProperties(props Properties) {
+	if m.mesh != nil {
+		props.SetString("meshFile", m.mesh.FileName())
+	}
+	props.SetInt("textureIndex", m.textureIndex)
 }
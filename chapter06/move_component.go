@@ -60,7 +60,16 @@ func (m *moveComponent) SetForwardSpeed(speed float32) {
 	m.forwardSpeed = speed
 }
 
-func (m *moveComponent) Destroy() {
-	owner := m.GetOwner()
-	owner.RemoveComponent(m)
+func (m *moveComponent) GetTypeName() string {
+	return "MoveComponent"
+}
+
+func (m *moveComponent) LoadProperties(props Properties) {
+	props.GetFloat("forwardSpeed", &m.forwardSpeed)
+	props.GetFloat("angularSpeed", &m.angularSpeed)
+}
+
+func (m *moveComponent) SaveProperties(props Properties) {
+	props.SetFloat("forwardSpeed", m.forwardSpeed)
+	props.SetFloat("angularSpeed", m.angularSpeed)
 }
@@ -0,0 +1,66 @@
+package chapter06
+
+import (
+	"slices"
+
+	"github.com/ishtaka/go-game-programming/chapter06/math"
+)
+
+// CollisionInfo is where a segment cast hit a box.
+type CollisionInfo struct {
+	// Point of collision
+	Point math.Vector3
+	// Normal of the box's side at the point
+	Normal math.Vector3
+	// Component collided with
+	Box BoxComponent
+	// Owning actor of the component
+	Actor Actor
+}
+
+// PhysWorld keeps track of every BoxComponent in the game.
+type PhysWorld struct {
+	boxes []BoxComponent
+}
+
+func NewPhysWorld() *PhysWorld {
+	return &PhysWorld{}
+}
+
+// SegmentCast returns the first box the segment hits, going from Start to End.
+func (p *PhysWorld) SegmentCast(l math.LineSegment) (CollisionInfo, bool) {
+	var info CollisionInfo
+	closestT := float32(math.Infinity)
+	for _, box := range p.boxes {
+		if !box.HasBox() || box.GetOwner().GetState() == Dead {
+			continue
+		}
+
+		hit, ok := math.SegmentCast(l, box.GetWorldBox())
+		if ok && hit.T < closestT {
+			closestT = hit.T
+			info = CollisionInfo{
+				Point:  hit.Point,
+				Normal: hit.Normal,
+				Box:    box,
+				Actor:  box.GetOwner(),
+			}
+		}
+	}
+
+	return info, closestT <= 1
+}
+
+func (p *PhysWorld) AddBox(box BoxComponent) {
+	p.boxes = append(p.boxes, box)
+}
+
+func (p *PhysWorld) RemoveBox(box BoxComponent) {
+	p.boxes = slices.DeleteFunc(p.boxes, func(b BoxComponent) bool {
+		return b == box
+	})
+}
+
+func (p *PhysWorld) GetBoxes() []BoxComponent {
+	return p.boxes
+}
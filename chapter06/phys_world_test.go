@@ -0,0 +1,72 @@
+package chapter06
+
+import (
+	"testing"
+
+	"github.com/ishtaka/go-game-programming/chapter06/math"
+)
+
+// newWall adds an actor with a 2 unit cube box, scaled and placed at pos
+func newWall(g *Game, pos math.Vector3, scale float32) Actor {
+	wall := NewActor(g)
+	wall.SetPosition(pos)
+	wall.SetScale(scale)
+	bc := NewBoxComponent(wall, DefaultUpdateOrder)
+	bc.SetObjectBox(math.AABB{Min: math.Vector3{X: -1, Y: -1, Z: -1}, Max: math.Vector3{X: 1, Y: 1, Z: 1}})
+	wall.AddComponent(bc)
+	g.AddActor(wall)
+	wall.ComputeWorldTransform()
+
+	return wall
+}
+
+func clearScene(g *Game) {
+	for len(g.GetActors()) > 0 {
+		g.DestroyActor(g.GetActors()[0])
+	}
+}
+
+func TestSegmentCastFindsNearestBox(t *testing.T) {
+	g := newHeadlessGame(t)
+	clearScene(g)
+
+	far := newWall(g, math.Vector3{X: 500}, 50)
+	near := newWall(g, math.Vector3{X: 200}, 50)
+
+	info, ok := g.GetPhysWorld().SegmentCast(math.LineSegment{End: math.Vector3{X: 1000}})
+	if !ok || info.Actor != near {
+		t.Fatalf("expected to hit the near wall, got %v", info.Actor)
+	}
+	if !nearlyEqual(info.Point, math.Vector3{X: 150}) || !nearlyEqual(info.Normal, math.Vector3{X: -1}) {
+		t.Errorf("expected hit at (150, 0, 0) facing back, got %v %v", info.Point, info.Normal)
+	}
+
+	g.DestroyActor(near)
+	if info, ok := g.GetPhysWorld().SegmentCast(math.LineSegment{End: math.Vector3{X: 1000}}); !ok || info.Actor != far {
+		t.Errorf("expected to hit the far wall once the near one is gone")
+	}
+	if _, ok := g.GetPhysWorld().SegmentCast(math.LineSegment{End: math.Vector3{Y: 1000}}); ok {
+		t.Errorf("expected no hit away from the walls")
+	}
+}
+
+func TestCameraStopsAtWall(t *testing.T) {
+	g := newHeadlessGame(t)
+	clearScene(g)
+
+	newWall(g, math.Vector3{X: 200}, 50)
+	camera := NewCameraActor(g)
+	g.AddActor(camera)
+	camera.moveComp.SetForwardSpeed(300)
+
+	for range 120 {
+		g.Step(1.0 / 60.0)
+	}
+
+	if x := camera.GetPosition().X; x > 150-cameraRadius+0.01 {
+		t.Errorf("expected the camera to stop before the wall at 150, got x = %f", x)
+	}
+	if x := camera.GetPosition().X; x < 100 {
+		t.Errorf("expected the camera to reach the wall, got x = %f", x)
+	}
+}
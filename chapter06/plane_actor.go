@@ -3,6 +3,7 @@ package chapter06
 type PlaneActor struct {
 	Actor
 	mc MeshComponent
+	bc BoxComponent
 }
 
 func NewPlaneActor(game *Game) *PlaneActor {
@@ -14,13 +15,17 @@ func NewPlaneActor(game *Game) *PlaneActor {
 	mc.SetMesh(m)
 	a.AddComponent(mc)
 
+	// Collide with the plane's mesh
+	bc := NewBoxComponent(a, DefaultUpdateOrder)
+	a.AddComponent(bc)
+
 	return &PlaneActor{
 		Actor: a,
 		mc:    mc,
+		bc:    bc,
 	}
 }
 
-func (p *PlaneActor) Destroy() {
-	p.GetGame().RemoveActor(p)
-	p.DestroyComponents()
+func (p *PlaneActor) GetTypeName() string {
+	return "PlaneActor"
 }
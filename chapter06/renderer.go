@@ -53,6 +53,9 @@ type Renderer struct {
 	ambientLight math.Vector3
 	dirLight     *DirectionalLight
 
+	// Whether the renderer has no window or OpenGL context
+	headless bool
+
 	// Window
 	window *sdl.Window
 	// OpenGL context
@@ -111,6 +114,9 @@ func (r *Renderer) Initialize(screenWidth, screenHeight float32) error {
 		return err
 	}
 
+	// Wait for vertical sync when swapping buffers
+	_ = sdl.GLSetSwapInterval(1)
+
 	// On some platforms, GLEW will emit a benign error code,
 	// so clear it
 	gl.GetError()
@@ -126,6 +132,19 @@ func (r *Renderer) Initialize(screenWidth, screenHeight float32) error {
 	return nil
 }
 
+// InitializeHeadless sets up the renderer without a window or OpenGL context.
+// Meshes still load their data, but no textures or vertex arrays are created
+// and Draw does nothing.
+func (r *Renderer) InitializeHeadless(screenWidth, screenHeight float32) {
+	r.screenWidth = screenWidth
+	r.screenHeight = screenHeight
+	r.headless = true
+}
+
+func (r *Renderer) IsHeadless() bool {
+	return r.headless
+}
+
 func (r *Renderer) loadShaders() bool {
 	// Create sprite shader
 	r.spriteShader = NewShader()
@@ -170,7 +189,13 @@ func (r *Renderer) createSpriteVerts() {
 	r.spriteVerts = NewVertexArray(vertices, 4, indices, 6)
 }
 
-func (r *Renderer) Draw() {
+// Draw renders the scene, interpolating transforms by alpha
+// between the last two updates.
+func (r *Renderer) Draw(alpha float32) {
+	if r.headless {
+		return
+	}
+
 	// Set the clear color
 	gl.ClearColor(0.86, 0.86, 0.86, 1.0)
 	// Clear the color buffer
@@ -189,7 +214,7 @@ func (r *Renderer) Draw() {
 	r.SetLightUniforms(r.meshShader)
 
 	for _, mc := range r.meshComps {
-		mc.Draw(r.meshShader)
+		mc.Draw(r.meshShader, alpha)
 	}
 
 	// Draw all sprite components
@@ -204,7 +229,7 @@ func (r *Renderer) Draw() {
 	r.spriteVerts.SetActive()
 
 	for _, s := range r.sprites {
-		s.Draw(r.spriteShader)
+		s.Draw(r.spriteShader, alpha)
 	}
 
 	// Swap the buffers
@@ -247,6 +272,11 @@ func (r *Renderer) GetTexture(fileName string) *Texture {
 		return tex
 	}
 
+	// There is no OpenGL context to create textures with
+	if r.headless {
+		return nil
+	}
+
 	tex := NewTexture()
 	if tex.Load(fileName) {
 		r.textures[fileName] = tex
@@ -275,6 +305,10 @@ func (r *Renderer) SetViewMatrix(view math.Matrix4) {
 	r.view = view
 }
 
+func (r *Renderer) GetAmbientLight() math.Vector3 {
+	return r.ambientLight
+}
+
 func (r *Renderer) SetAmbientLight(ambient math.Vector3) {
 	r.ambientLight = ambient
 }
@@ -0,0 +1,321 @@
+package chapter06
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ishtaka/go-game-programming/chapter06/math"
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+const sceneVersion = 1
+
+// ActorFactory creates an actor of a registered type for the scene loader.
+type ActorFactory func(game *Game) Actor
+
+// ComponentFactory creates a component of a registered type for the scene loader.
+type ComponentFactory func(owner Actor) Component
+
+var actorFactories = map[string]ActorFactory{
+	"Actor": NewActor,
+	"PlaneActor": func(game *Game) Actor {
+		return NewPlaneActor(game)
+	},
+	"CameraActor": func(game *Game) Actor {
+		return NewCameraActor(game)
+	},
+}
+
+var componentFactories = map[string]ComponentFactory{
+	"BoxComponent": func(owner Actor) Component {
+		return NewBoxComponent(owner, DefaultUpdateOrder)
+	},
+	"MeshComponent": func(owner Actor) Component {
+		return NewMeshComponent(owner, DefaultUpdateOrder)
+	},
+	"MoveComponent": func(owner Actor) Component {
+		return NewMoveComponent(owner, DefaultUpdateOrder)
+	},
+}
+
+// RegisterActor lets scene files create actors of typeName.
+// typeName should match the actor's GetTypeName.
+func RegisterActor(typeName string, factory ActorFactory) {
+	actorFactories[typeName] = factory
+}
+
+// RegisterComponent lets scene files create components of typeName.
+// typeName should match the component's GetTypeName.
+func RegisterComponent(typeName string, factory ComponentFactory) {
+	componentFactories[typeName] = factory
+}
+
+// Properties are the named values of an actor or component in a scene file.
+// The getters leave the value alone and return false if the property is missing or invalid.
+type Properties map[string]json.RawMessage
+
+func (p Properties) get(name string, v any) bool {
+	data, ok := p[name]
+	if !ok {
+		return false
+	}
+
+	return json.Unmarshal(data, v) == nil
+}
+
+func (p Properties) set(name string, v any) {
+	// Only plain values are set, so this can't fail
+	data, _ := json.Marshal(v)
+	p[name] = data
+}
+
+func (p Properties) GetInt(name string, v *int) bool {
+	return p.get(name, v)
+}
+
+func (p Properties) SetInt(name string, v int) {
+	p.set(name, v)
+}
+
+func (p Properties) GetFloat(name string, v *float32) bool {
+	return p.get(name, v)
+}
+
+func (p Properties) SetFloat(name string, v float32) {
+	p.set(name, v)
+}
+
+func (p Properties) GetBool(name string, v *bool) bool {
+	return p.get(name, v)
+}
+
+func (p Properties) SetBool(name string, v bool) {
+	p.set(name, v)
+}
+
+func (p Properties) GetString(name string, v *string) bool {
+	return p.get(name, v)
+}
+
+func (p Properties) SetString(name string, v string) {
+	p.set(name, v)
+}
+
+// GetVector3 reads a vector stored as [x, y, z].
+func (p Properties) GetVector3(name string, v *math.Vector3) bool {
+	var a [3]float32
+	if !p.get(name, &a) {
+		return false
+	}
+
+	*v = math.Vector3{X: a[0], Y: a[1], Z: a[2]}
+	return true
+}
+
+func (p Properties) SetVector3(name string, v math.Vector3) {
+	p.set(name, [3]float32{v.X, v.Y, v.Z})
+}
+
+// GetQuaternion reads a quaternion stored as [x, y, z, w].
+func (p Properties) GetQuaternion(name string) (*math.Quaternion, bool) {
+	var a [4]float32
+	if !p.get(name, &a) {
+		return nil, false
+	}
+
+	return math.NewQuaternion(a[0], a[1], a[2], a[3]), true
+}
+
+func (p Properties) SetQuaternion(name string, q *math.Quaternion) {
+	x, y, z, w := q.Get()
+	p.set(name, [4]float32{x, y, z, w})
+}
+
+type sceneData struct {
+	Version          int         `json:"version"`
+	GlobalProperties Properties  `json:"globalProperties"`
+	Actors           []actorData `json:"actors"`
+}
+
+type actorData struct {
+	Type       string          `json:"type"`
+	Properties Properties      `json:"properties"`
+	Components []componentData `json:"components,omitempty"`
+	Children   []actorData     `json:"children,omitempty"`
+}
+
+type componentData struct {
+	Type       string     `json:"type"`
+	Properties Properties `json:"properties"`
+}
+
+// LoadScene adds the lights and actors of a scene file to the game.
+func (g *Game) LoadScene(fileName string) bool {
+	f, err := assets.Open(fileName)
+	if err != nil {
+		sdl.Log("file not found: Scene %s %s", fileName, err)
+		return false
+	}
+	defer f.Close()
+
+	if err := g.ReadScene(f); err != nil {
+		sdl.Log("scene %s is not valid: %s", fileName, err)
+		return false
+	}
+
+	return true
+}
+
+// SaveScene writes the lights and actors of the game to a scene file.
+func (g *Game) SaveScene(fileName string) bool {
+	f, err := os.Create(fileName)
+	if err != nil {
+		sdl.Log("failed to create scene %s: %s", fileName, err)
+		return false
+	}
+	defer f.Close()
+
+	if err := g.WriteScene(f); err != nil {
+		sdl.Log("failed to write scene %s: %s", fileName, err)
+		return false
+	}
+
+	return f.Close() == nil
+}
+
+// ReadScene adds the lights and actors of a scene to the game.
+// Actors and components of unknown types are skipped.
+func (g *Game) ReadScene(r io.Reader) error {
+	var doc sceneData
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return err
+	}
+	if doc.Version != sceneVersion {
+		return fmt.Errorf("scene version %d is not supported", doc.Version)
+	}
+
+	g.loadGlobalProperties(doc.GlobalProperties)
+
+	for i := range doc.Actors {
+		g.loadActor(&doc.Actors[i])
+	}
+
+	return nil
+}
+
+// WriteScene writes the lights and actors of the game as JSON.
+// Children are written inside their parent.
+func (g *Game) WriteScene(w io.Writer) error {
+	doc := sceneData{
+		Version:          sceneVersion,
+		GlobalProperties: Properties{},
+	}
+	g.saveGlobalProperties(doc.GlobalProperties)
+
+	for _, a := range g.actors {
+		if a.GetParent() == nil {
+			doc.Actors = append(doc.Actors, saveActor(a))
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	return enc.Encode(&doc)
+}
+
+func (g *Game) loadGlobalProperties(props Properties) {
+	var ambient math.Vector3
+	if props.GetVector3("ambientLight", &ambient) {
+		g.renderer.SetAmbientLight(ambient)
+	}
+
+	var dirProps Properties
+	if props.get("directionalLight", &dirProps) {
+		dir := g.renderer.GetDirectionalLight()
+		dirProps.GetVector3("direction", &dir.Direction)
+		dirProps.GetVector3("color", &dir.DiffuseColor)
+		dirProps.GetVector3("specularColor", &dir.SpecColor)
+	}
+}
+
+func (g *Game) saveGlobalProperties(props Properties) {
+	props.SetVector3("ambientLight", g.renderer.GetAmbientLight())
+
+	dir := g.renderer.GetDirectionalLight()
+	dirProps := Properties{}
+	dirProps.SetVector3("direction", dir.Direction)
+	dirProps.SetVector3("color", dir.DiffuseColor)
+	dirProps.SetVector3("specularColor", dir.SpecColor)
+	props.set("directionalLight", dirProps)
+}
+
+func (g *Game) loadActor(data *actorData) Actor {
+	factory, ok := actorFactories[data.Type]
+	if !ok {
+		sdl.Log("unknown actor type %s", data.Type)
+		return nil
+	}
+
+	a := factory(g)
+	a.LoadProperties(data.Properties)
+
+	// Components the actor made itself (like a PlaneActor's mesh) are used first
+	used := make(map[Component]bool)
+	for _, cd := range data.Components {
+		var c Component
+		for _, existing := range a.GetComponents() {
+			if existing.GetTypeName() == cd.Type && !used[existing] {
+				c = existing
+				break
+			}
+		}
+
+		if c == nil {
+			factory, ok := componentFactories[cd.Type]
+			if !ok {
+				sdl.Log("unknown component type %s", cd.Type)
+				continue
+			}
+			c = factory(a)
+			a.AddComponent(c)
+		}
+
+		used[c] = true
+		c.LoadProperties(cd.Properties)
+	}
+
+	g.AddActor(a)
+
+	for i := range data.Children {
+		if child := g.loadActor(&data.Children[i]); child != nil {
+			AddChild(a, child)
+		}
+	}
+
+	return a
+}
+
+func saveActor(a Actor) actorData {
+	data := actorData{
+		Type:       a.GetTypeName(),
+		Properties: Properties{},
+	}
+	a.SaveProperties(data.Properties)
+
+	for _, c := range a.GetComponents() {
+		cd := componentData{
+			Type:       c.GetTypeName(),
+			Properties: Properties{},
+		}
+		c.SaveProperties(cd.Properties)
+		data.Components = append(data.Components, cd)
+	}
+
+	for _, child := range a.GetChildren() {
+		data.Children = append(data.Children, saveActor(child))
+	}
+
+	return data
+}
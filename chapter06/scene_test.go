@@ -0,0 +1,115 @@
+package chapter06
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/ishtaka/go-game-programming/chapter06/math"
+)
+
+func TestLoadScene(t *testing.T) {
+	g := newHeadlessGame(t)
+
+	// 100 floor tiles, 40 walls, the cube, the sphere and the camera
+	if n := len(g.GetActors()); n != 143 {
+		t.Errorf("expected 143 actors in the scene, got %d", n)
+	}
+	if g.GetCamera() == nil {
+		t.Errorf("expected the scene to set the camera")
+	}
+	if ambient := g.GetRenderer().GetAmbientLight(); !nearlyEqual(ambient, math.Vector3{X: 0.2, Y: 0.2, Z: 0.2}) {
+		t.Errorf("expected ambient light from the scene, got %v", ambient)
+	}
+}
+
+func TestSceneRoundTrip(t *testing.T) {
+	g := newHeadlessGame(t)
+	for len(g.GetActors()) > 0 {
+		g.DestroyActor(g.GetActors()[0])
+	}
+
+	parent := NewCameraActor(g)
+	parent.SetPosition(math.Vector3{X: 10, Y: 20, Z: 30})
+	parent.SetRotation(math.NewQuaternionFromVec(math.Vector3UnitZ, math.PiOver2))
+	parent.moveComp.SetForwardSpeed(150)
+	g.AddActor(parent)
+
+	child := NewActor(g)
+	child.SetPosition(math.Vector3{X: 5})
+	child.SetScale(2)
+	child.SetState(Paused)
+	g.AddActor(child)
+	AddChild(parent, child)
+
+	var buf bytes.Buffer
+	if err := g.WriteScene(&buf); err != nil {
+		t.Fatalf("failed to write scene: %s", err)
+	}
+
+	loaded := newHeadlessGame(t)
+	for len(loaded.GetActors()) > 0 {
+		loaded.DestroyActor(loaded.GetActors()[0])
+	}
+	if err := loaded.ReadScene(&buf); err != nil {
+		t.Fatalf("failed to read scene: %s", err)
+	}
+
+	if n := len(loaded.GetActors()); n != 2 {
+		t.Fatalf("expected 2 actors, got %d", n)
+	}
+	camera := loaded.GetCamera()
+	if camera == nil {
+		t.Fatalf("expected a camera actor")
+	}
+	if !nearlyEqual(camera.GetPosition(), parent.GetPosition()) {
+		t.Errorf("expected position %v, got %v", parent.GetPosition(), camera.GetPosition())
+	}
+	if !nearlyEqual(camera.GetForward(), parent.GetForward()) {
+		t.Errorf("expected forward %v, got %v", parent.GetForward(), camera.GetForward())
+	}
+	// The camera's own move component is reused rather than adding another
+	if n := len(camera.GetComponents()); n != 1 {
+		t.Errorf("expected 1 component on the camera, got %d", n)
+	}
+	if speed := camera.moveComp.GetForwardSpeed(); speed != 150 {
+		t.Errorf("expected forward speed 150, got %f", speed)
+	}
+
+	if len(camera.GetChildren()) != 1 {
+		t.Fatalf("expected the camera to have 1 child, got %d", len(camera.GetChildren()))
+	}
+	c := camera.GetChildren()[0]
+	if c.GetScale() != 2 || c.GetState() != Paused {
+		t.Errorf("expected child scale 2 and Paused, got %f and %s", c.GetScale(), c.GetState())
+	}
+	if !nearlyEqual(c.GetWorldPosition(), child.GetWorldPosition()) {
+		t.Errorf("expected child world position %v, got %v", child.GetWorldPosition(), c.GetWorldPosition())
+	}
+}
+
+func TestReadSceneSkipsUnknownTypes(t *testing.T) {
+	g := newHeadlessGame(t)
+	count := len(g.GetActors())
+
+	scene := `{"version": 1, "actors": [
+		{"type": "Spaceship"},
+		{"type": "Actor", "components": [{"type": "Thruster"}, {"type": "MoveComponent", "properties": {"angularSpeed": 1.5}}]}
+	]}`
+	if err := g.ReadScene(strings.NewReader(scene)); err != nil {
+		t.Fatalf("failed to read scene: %s", err)
+	}
+
+	if n := len(g.GetActors()) - count; n != 1 {
+		t.Fatalf("expected 1 actor to be added, got %d", n)
+	}
+	a := g.GetActors()[len(g.GetActors())-1]
+	mc, ok := GetComponent[MoveComponent](a)
+	if !ok || mc.GetAngularSpeed() != 1.5 {
+		t.Errorf("expected a move component with angular speed 1.5")
+	}
+
+	if err := g.ReadScene(strings.NewReader(`{"version": 2}`)); err == nil {
+		t.Errorf("expected an error for an unsupported version")
+	}
+}